@@ -3,31 +3,36 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/app"
+	"github.com/hassiimykyta/life-rpg/pkg/config"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
 )
 
 func main() {
-	a, err := app.New()
-	if err != nil {
-		log.Fatal(err)
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		if err := config.Print(os.Stdout, app.ConfigOpts...); err != nil {
+			log.Fatalf("config: %v", err)
+		}
+		return
 	}
 
-	go func() {
-		if err := a.Start(); err != nil {
-			log.Fatal(err)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.Migrate(context.Background(), os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
 		}
-	}()
-
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
-	<-stop
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_ = a.Stop(ctx)
+	a, err := app.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := a.Run(context.Background()); err != nil {
+		slog.Error("auth-svc stopped with error", logx.Err(err))
+		os.Exit(1)
+	}
+	slog.Info("auth-svc stopped")
 }
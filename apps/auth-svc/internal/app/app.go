@@ -2,46 +2,71 @@ package app
 
 import (
 	"context"
-	"log"
-	"net"
+	"crypto/tls"
+	"log/slog"
+	"time"
 
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/auth"
-	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/models"
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/repo"
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/security/password"
 	"github.com/hassiimykyta/life-rpg/pkg/config"
 	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"github.com/hassiimykyta/life-rpg/pkg/grpcserver"
+	"github.com/hassiimykyta/life-rpg/pkg/health"
+	"github.com/hassiimykyta/life-rpg/pkg/helpers"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
 	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+	"github.com/hassiimykyta/life-rpg/pkg/lifecycle"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
+	"github.com/hassiimykyta/life-rpg/pkg/redisx"
+	"github.com/hassiimykyta/life-rpg/pkg/tlsx"
+	"github.com/hassiimykyta/life-rpg/pkg/tracing"
 	"github.com/hassiimykyta/life-rpg/pkg/ulid"
 	authv1 "github.com/hassiimykyta/life-rpg/services/auth/v1"
-	"google.golang.org/grpc"
+	userv1 "github.com/hassiimykyta/life-rpg/services/user/v1"
 	"gorm.io/gorm/logger"
 )
 
 type App struct {
-	cfg   *config.Config
-	db    *db.Conn
-	grpc  *grpc.Server
-	lis   net.Listener
-	kafka *kafka.ProducerFactory
+	cfg *config.Config
+	lc  *lifecycle.Runner
 }
 
+const grpcDefaultTimeout = 15 * time.Second
+
 func New() (*App, error) {
-	cfg, err := config.Load(config.WithDB(), config.WithJWT())
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	logx.Setup(logx.Options{Service: "auth-svc", Env: cfg.App.Env, Level: cfg.App.LogLevel, Format: cfg.App.LogFormat})
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		Service:     "auth-svc",
+		Env:         cfg.App.Env,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	brokers := helpers.Csv(helpers.GetEnv("KAFKA_BROKERS", "kafka:9092"))
 	producer := kafka.NewProducerFactory(kafka.ProducerFactoryConfig{
-		Brokers: []string{"kafka:9092"},
+		Brokers: brokers,
 	})
-	defer producer.Close()
 
 	conn, err := db.Open(db.Options{
 		DSN:           cfg.DB.DSN,
+		ReplicaDSNs:   cfg.DB.ReplicaDSNs,
 		MaxOpen:       cfg.DB.MaxOpen,
 		MaxIdle:       cfg.DB.MaxIdle,
 		MaxIdleTime:   cfg.DB.MaxIdleTime,
+		MaxLifetime:   cfg.DB.MaxLifetime,
+		QueryTimeout:  cfg.DB.QueryTimeout,
+		SlowThreshold: cfg.DB.SlowThreshold,
 		LogLevel:      logger.Info,
 		SingularTable: true,
 	})
@@ -49,53 +74,133 @@ func New() (*App, error) {
 		return nil, err
 	}
 
-	if err := conn.Gorm.AutoMigrate(&models.Identity{}); err != nil {
+	if cfg.DB.MigrateOnStart {
+		if err := migrateUp(context.Background(), conn); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := metrics.RegisterDBStats("auth", conn.SQL); err != nil {
+		return nil, err
+	}
+
+	rdb, closeRedis, err := redisx.New(context.Background(), redisx.Config{
+		Mode:             cfg.Cache.Mode,
+		Addrs:            cfg.Cache.Addrs,
+		MasterName:       cfg.Cache.MasterName,
+		Username:         cfg.Cache.Username,
+		Password:         cfg.Cache.Password,
+		SentinelPassword: cfg.Cache.SentinelPassword,
+		DB:               cfg.Cache.DB,
+		DialTimeout:      cfg.Cache.DialTimeout,
+		ReadTimeout:      cfg.Cache.ReadTimeout,
+		WriteTimeout:     cfg.Cache.WriteTimeout,
+		PoolSize:         cfg.Cache.PoolSize,
+		MinIdleConns:     cfg.Cache.MinIdleConns,
+		TLSEnabled:       cfg.Cache.TLSEnabled,
+	})
+	if err != nil {
 		return nil, err
 	}
+	cache := redisx.NewCache(rdb, "auth:")
 
 	repository := repo.NewIdentityRepo(conn.Gorm)
 	idgen := ulid.NewULIDGenerator()
 	hasher := password.Bcrypt{}
 
-	svc := auth.New(repository, hasher, idgen, producer)
-
-	s := grpc.NewServer()
-	authv1.RegisterAuthServiceServer(s, svc)
+	svc := auth.New(repository, hasher, idgen, producer, cache)
+
+	var (
+		certs     *tlsx.Reloader
+		serverTLS *tls.Config
+	)
+	if cfg.TLS.Enabled {
+		certs, err = tlsx.NewReloader(tlsx.Options{
+			CertFile:       cfg.TLS.CertFile,
+			KeyFile:        cfg.TLS.KeyFile,
+			CAFile:         cfg.TLS.CAFile,
+			ReloadInterval: cfg.TLS.ReloadInterval,
+		})
+		if err != nil {
+			return nil, err
+		}
+		serverTLS = certs.ServerConfig(cfg.TLS.AllowedPeers)
+	}
 
-	lis, err := net.Listen("tcp", ":"+cfg.App.Port)
+	jwtMgr := jwt.NewManager(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL)
+	gs, err := grpcserver.New(grpcserver.Options{
+		Addr:           ":" + cfg.App.Port,
+		TLS:            serverTLS,
+		Reflection:     cfg.App.Env == "dev",
+		DefaultTimeout: grpcDefaultTimeout,
+		Authenticate:   grpcserver.JWTAuth(jwtMgr),
+		// все методы AuthService вызываются до логина — токена ещё нет
+		PublicMethods: []string{"/" + authv1.AuthService_ServiceDesc.ServiceName + "/"},
+	})
 	if err != nil {
 		return nil, err
 	}
+	authv1.RegisterAuthServiceServer(gs.GRPC, svc)
+	userv1.RegisterUserServiceServer(gs.GRPC, auth.NewUserService(repository))
 
-	return &App{
-		cfg:   cfg,
-		db:    conn,
-		grpc:  s,
-		lis:   lis,
-		kafka: producer,
-	}, nil
+	checker := health.New(health.Options{})
+	checker.Add("db", conn.HealthPing)
+	checker.Add("kafka", func(ctx context.Context) error { return kafka.Ping(ctx, brokers) })
+	checker.Add("redis", cache.Ping)
+	checker.RegisterGRPC(gs.GRPC)
 
-}
+	admin, err := metrics.NewAdminServer(":"+cfg.App.AdminPort, checker.Mount)
+	if err != nil {
+		return nil, err
+	}
 
-func (a *App) Start() error {
-	log.Printf("auth-svc listening on %s (env=%s)", a.lis.Addr(), a.cfg.App.Env)
-	return a.grpc.Serve(a.lis)
-}
+	watcher := config.NewWatcher(cfg, ConfigOpts...)
+	watcher.OnReload(func(_, cur *config.Config) {
+		logx.SetLevel(cur.App.LogLevel)
+	})
 
-func (a *App) Stop(ctx context.Context) error {
-	stopped := make(chan struct{})
-	go func() {
-		a.grpc.GracefulStop()
-		close(stopped)
-	}()
-	select {
-	case <-stopped:
-	case <-ctx.Done():
-		a.grpc.Stop()
+	lc := lifecycle.New(lifecycle.Options{
+		ShutdownTimeout: cfg.App.ShutdownTimeout,
+		DrainDelay:      cfg.App.DrainDelay,
+		Drain:           checker.Shutdown,
+	})
+	lc.Append(
+		lifecycle.Hook{Name: "tracing", Stop: shutdownTracing},
+		lifecycle.Hook{Name: "db", Stop: func(context.Context) error { return conn.SQL.Close() }},
+		lifecycle.Hook{Name: "redis", Stop: func(context.Context) error { return closeRedis() }},
+		lifecycle.Hook{Name: "kafka", Stop: func(context.Context) error { return producer.Close() }},
+	)
+	if certs != nil {
+		lc.Go("tls reloader", func(ctx context.Context) error { certs.Run(ctx); return nil })
 	}
+	lc.Go("config watcher", func(ctx context.Context) error { watcher.Run(ctx, configReloadInterval); return nil })
+	lc.Append(
+		lifecycle.Hook{Name: "health", Start: func(ctx context.Context) error { checker.Start(ctx); return nil }},
+		lifecycle.Hook{
+			Name:  "admin",
+			Start: func(context.Context) error { admin.Start(); return nil },
+			Stop:  admin.Shutdown,
+		},
+		lifecycle.Hook{
+			Name: "grpc",
+			Start: func(context.Context) error {
+				gs.Start()
+				slog.Info("auth-svc listening", "addr", gs.Addr().String(), "admin_port", cfg.App.AdminPort, "env", cfg.App.Env)
+				return nil
+			},
+			Stop: gs.Shutdown,
+		},
+	)
+
+	// упавший Serve (не Shutdown) останавливает сервис, а не оставляет его без порта
+	lc.Watch("admin", admin.Err())
+	lc.Watch("grpc", gs.Err())
+
+	return &App{cfg: cfg, lc: lc}, nil
+}
 
-	if a.db != nil && a.db.SQL != nil {
-		_ = a.db.SQL.Close()
-	}
-	return nil
+// Run работает до SIGINT/SIGTERM и останавливает сервис: сначала gRPC, потом
+// admin, фоновые задачи и соединения с хранилищами.
+func (a *App) Run(ctx context.Context) error {
+	return a.lc.Run(ctx)
 }
@@ -0,0 +1,17 @@
+package app
+
+import (
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/config"
+)
+
+// configReloadInterval — как часто проверять изменения CONFIG_FILE.
+const configReloadInterval = 10 * time.Second
+
+// ConfigOpts — секции конфига сервиса; их же использует `config print`.
+var ConfigOpts = []config.Option{config.WithDB(), config.WithCache(), config.WithJWT(), config.WithTracing(), config.WithTLS()}
+
+func loadConfig() (*config.Config, error) {
+	return config.Load(ConfigOpts...)
+}
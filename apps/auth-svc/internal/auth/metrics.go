@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Запросы, не прошедшие Validate, до хендлеров не доходят: они видны в
+// grpc_server_handled_total{code="InvalidArgument"}.
+var (
+	registrations = metrics.Factory().NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_registrations_total",
+		Help: "Registration attempts by outcome.",
+	}, []string{"outcome"})
+
+	logins = metrics.Factory().NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Login attempts by outcome.",
+	}, []string{"outcome"})
+)
+
+func outcome(err error) string {
+	switch status.Code(err) {
+	case codes.OK:
+		return "success"
+	case codes.InvalidArgument:
+		return "invalid_input"
+	case codes.Unauthenticated:
+		return "invalid_credentials"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"github.com/hassiimykyta/life-rpg/pkg/redisx"
+	"gorm.io/gorm"
+)
+
+const (
+	resolveTTL         = 10 * time.Minute
+	resolveNegativeTTL = 30 * time.Second
+)
+
+// resolved — то, что отдаёт Resolve; хэш пароля в кэш не попадает.
+type resolved struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+func resolveKey(field, value string) string { return "resolve:" + field + ":" + value }
+
+func userTag(userID string) string { return "user:" + userID }
+
+var resolveOpts = redisx.LoadOptions[resolved]{
+	TTL:         resolveTTL,
+	NegativeTTL: resolveNegativeTTL,
+	Tags:        func(r resolved) []string { return []string{userTag(r.UserID)} },
+}
+
+// resolve ищет identity через кэш, без Redis — сразу в БД.
+// Отсутствие записи — redisx.ErrNotFound. Загрузка идёт из primary: промах
+// кэшируется, и реплика, ещё не получившая Register, закрепила бы его.
+func (s *Service) resolve(ctx context.Context, field, value string, find func(context.Context, string) (models.Identity, error)) (resolved, error) {
+	load := func(ctx context.Context) (resolved, error) {
+		ide, err := find(db.WithPrimary(ctx), value)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resolved{}, redisx.ErrNotFound
+		}
+		if err != nil {
+			return resolved{}, err
+		}
+		return resolved{UserID: ide.UserId, Email: ide.Email, Username: ide.Username}, nil
+	}
+	if s.cache == nil {
+		return load(ctx)
+	}
+	return redisx.GetOrLoad(ctx, s.cache, resolveKey(field, value), resolveOpts, load)
+}
+
+// forgetIdentity сбрасывает закэшированные промахи по email/username после регистрации,
+// иначе новый пользователь ещё resolveNegativeTTL «не существует». Загрузка,
+// начатая до Del, своё значение уже не запишет (см. redisx.GetOrLoad).
+func (s *Service) forgetIdentity(ctx context.Context, userID, email, username string) error {
+	if s.cache == nil {
+		return nil
+	}
+	if err := s.cache.Del(ctx, resolveKey("email", email), resolveKey("username", username), resolveKey("user_id", userID)); err != nil {
+		return err
+	}
+	return s.cache.InvalidateTags(ctx, userTag(userID))
+}
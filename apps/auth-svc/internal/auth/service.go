@@ -3,19 +3,25 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/models"
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/repo"
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/security/password"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
 	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/redisx"
 	"github.com/hassiimykyta/life-rpg/pkg/ulid"
 	authv1 "github.com/hassiimykyta/life-rpg/services/auth/v1"
 	usereventsv1 "github.com/hassiimykyta/life-rpg/services/events/user/v1"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
 type Service struct {
@@ -24,23 +30,42 @@ type Service struct {
 	hash  password.Hasher
 	ids   *ulid.ULIDGenerator
 	kafka *kafka.ProducerFactory
+	cache *redisx.Cache
 }
 
-func New(r *repo.IdentityRepo, h password.Hasher, g *ulid.ULIDGenerator, kf *kafka.ProducerFactory) *Service {
-	return &Service{repo: r, hash: h, ids: g, kafka: kf}
+// New собирает сервис; c может быть nil — тогда Resolve ходит прямо в БД.
+func New(r *repo.IdentityRepo, h password.Hasher, g *ulid.ULIDGenerator, kf *kafka.ProducerFactory, c *redisx.Cache) *Service {
+	return &Service{repo: r, hash: h, ids: g, kafka: kf, cache: c}
 }
 
 func normIdentifier(ide string) string {
 	return strings.TrimSpace(strings.ToLower(ide))
 }
 
-func (s *Service) publishUserRegistered(ctx context.Context, id, email, username string) {
+// supportedLocales — языки шаблонов писем (notification-svc, mailer/locales).
+var supportedLocales = map[string]bool{"en": true, "ru": true, "uk": true}
+
+// normLocale сводит тег клиента (ru-RU, uk_UA) к языку шаблонов;
+// неизвестный или мусорный — "en", иначе он не влез бы в Identity.Locale.
+func normLocale(l string) string {
+	l = strings.TrimSpace(strings.ToLower(l))
+	if i := strings.IndexAny(l, "-_"); i > 0 {
+		l = l[:i]
+	}
+	if !supportedLocales[l] {
+		return "en"
+	}
+	return l
+}
+
+func (s *Service) publishUserRegistered(ctx context.Context, id, email, username, locale string) {
 	evt := &usereventsv1.UserRegistered{
 		Event:      "user.registered",
 		UserId:     id,
 		Email:      email,
 		Username:   username,
 		OccurredAt: time.Now().Unix(),
+		Locale:     locale,
 	}
 	b, _ := json.Marshal(evt)
 	p := s.kafka.Get("user.registered")
@@ -49,13 +74,16 @@ func (s *Service) publishUserRegistered(ctx context.Context, id, email, username
 }
 
 func (s *Service) Register(ctx context.Context, in *authv1.RegisterRequest) (*authv1.RegisterResponse, error) {
+	resp, err := s.register(ctx, in)
+	registrations.WithLabelValues(outcome(err)).Inc()
+	return resp, err
+}
+
+func (s *Service) register(ctx context.Context, in *authv1.RegisterRequest) (*authv1.RegisterResponse, error) {
 	email := normIdentifier(in.GetEmail())
 	username := normIdentifier(in.GetUsername())
 	password := in.GetPassword()
-
-	if email == "" || username == "" || len(password) < 6 {
-		return nil, status.Error(codes.InvalidArgument, "invalid input")
-	}
+	locale := normLocale(in.GetLocale())
 
 	id, err := s.ids.New()
 	if err != nil {
@@ -72,12 +100,19 @@ func (s *Service) Register(ctx context.Context, in *authv1.RegisterRequest) (*au
 		Email:        email,
 		Username:     username,
 		PasswordHash: h,
+		Locale:       locale,
 	})
+	if db.IsUniqueViolation(err) {
+		return nil, status.Error(codes.AlreadyExists, "email or username already taken")
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "create identity failed")
 	}
+	if err := s.forgetIdentity(ctx, id, email, username); err != nil {
+		slog.WarnContext(ctx, "resolve cache invalidation failed", "user_id", id, logx.Err(err))
+	}
 
-	s.publishUserRegistered(ctx, id, email, username)
+	s.publishUserRegistered(ctx, id, email, username, locale)
 
 	return &authv1.RegisterResponse{
 		UserId: id,
@@ -86,11 +121,13 @@ func (s *Service) Register(ctx context.Context, in *authv1.RegisterRequest) (*au
 }
 
 func (s *Service) Login(ctx context.Context, in *authv1.LoginRequest) (*authv1.LoginResponse, error) {
-	password := in.GetPassword()
+	resp, err := s.login(ctx, in)
+	logins.WithLabelValues(outcome(err)).Inc()
+	return resp, err
+}
 
-	if password == "" {
-		return nil, status.Error(codes.InvalidArgument, "password required")
-	}
+func (s *Service) login(ctx context.Context, in *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	password := in.GetPassword()
 
 	var (
 		ide models.Identity
@@ -100,33 +137,28 @@ func (s *Service) Login(ctx context.Context, in *authv1.LoginRequest) (*authv1.L
 	switch sub := in.Subject.(type) {
 	case *authv1.LoginRequest_Email:
 		email := normIdentifier(sub.Email)
-		if email == "" {
-			return nil, status.Error(codes.InvalidArgument, "email required")
-		}
 		ide, err = s.repo.FindByEmail(ctx, email)
 	case *authv1.LoginRequest_Username:
 		username := normIdentifier(sub.Username)
-		if username == "" {
-			return nil, status.Error(codes.InvalidArgument, "username required")
-		}
 		ide, err = s.repo.FindByUsername(ctx, username)
 
 	case *authv1.LoginRequest_UserId:
 		userId := sub.UserId
-		if userId == "" {
-			return nil, status.Error(codes.InvalidArgument, "user id required")
-		}
 		ide, err = s.repo.FindByUserID(ctx, userId)
 	default:
 		return nil, status.Error(codes.InvalidArgument, "oneof subject required")
 	}
 
+	// неизвестный логин неотличим от неверного пароля
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "lookup failed")
 	}
 
 	if !s.hash.Compare(ide.PasswordHash, password) {
-		return nil, status.Error(codes.PermissionDenied, "invalid credentials")
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
 	}
 
 	return &authv1.LoginResponse{
@@ -136,63 +168,54 @@ func (s *Service) Login(ctx context.Context, in *authv1.LoginRequest) (*authv1.L
 
 func (s *Service) Resolve(ctx context.Context, in *authv1.ResolveRequest) (*authv1.ResolveResponse, error) {
 	var (
-		ide models.Identity
+		r   resolved
 		err error
 	)
 
 	switch sub := in.Key.(type) {
 	case *authv1.ResolveRequest_Email:
 		email := normIdentifier(sub.Email)
-		if email == "" {
-			return nil, status.Error(codes.InvalidArgument, "email required")
-		}
-		ide, err = s.repo.FindByEmail(ctx, email)
+		r, err = s.resolve(ctx, "email", email, s.repo.FindByEmail)
 	case *authv1.ResolveRequest_Username:
 		username := normIdentifier(sub.Username)
-		if username == "" {
-			return nil, status.Error(codes.InvalidArgument, "username required")
-		}
-		ide, err = s.repo.FindByUsername(ctx, username)
+		r, err = s.resolve(ctx, "username", username, s.repo.FindByUsername)
 
 	case *authv1.ResolveRequest_UserId:
 		userId := sub.UserId
-		if userId == "" {
-			return nil, status.Error(codes.InvalidArgument, "user id required")
-		}
-		ide, err = s.repo.FindByUserID(ctx, userId)
+		r, err = s.resolve(ctx, "user_id", userId, s.repo.FindByUserID)
 	default:
 		return nil, status.Error(codes.InvalidArgument, "oneof subject required")
 	}
 
+	if errors.Is(err, redisx.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "identity not found")
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "lookup failed")
 	}
 
 	return &authv1.ResolveResponse{
-		UserId:   ide.UserId,
-		Email:    ide.Email,
-		Username: ide.Username,
+		UserId:   r.UserID,
+		Email:    r.Email,
+		Username: r.Username,
 	}, nil
 }
 func (s *Service) CheckAvailability(ctx context.Context, in *authv1.CheckAvailabilityRequest) (*authv1.CheckAvailabilityResponse, error) {
 	email := normIdentifier(in.GetEmail())
 	username := normIdentifier(in.GetUsername())
 
-	if email == "" {
-		return nil, status.Error(codes.InvalidArgument, "email required")
-	}
-	if username == "" {
-		return nil, status.Error(codes.InvalidArgument, "username required")
-	}
-
-	EmailAvailable := true
-	if _, err := s.repo.FindByEmail(ctx, email); err == nil {
-		EmailAvailable = false
+	// непереданное поле проверять не по чему — оно остаётся false
+	var EmailAvailable, UsernameAvailable bool
+	var err error
+	if email != "" {
+		if EmailAvailable, err = s.free(ctx, s.repo.FindByEmail, email); err != nil {
+			return nil, err
+		}
 	}
-
-	UsernameAvailable := true
-	if _, err := s.repo.FindByUsername(ctx, username); err == nil {
-		UsernameAvailable = false
+	if username != "" {
+		if UsernameAvailable, err = s.free(ctx, s.repo.FindByUsername, username); err != nil {
+			return nil, err
+		}
 	}
 
 	return &authv1.CheckAvailabilityResponse{
@@ -200,3 +223,15 @@ func (s *Service) CheckAvailability(ctx context.Context, in *authv1.CheckAvailab
 		UsernameAvailable: UsernameAvailable,
 	}, nil
 }
+
+// free — значение не занято; ошибка БД не выдаётся за «свободно».
+func (s *Service) free(ctx context.Context, find func(context.Context, string) (models.Identity, error), value string) (bool, error) {
+	_, err := find(ctx, value)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, status.Error(codes.Internal, "lookup failed")
+	}
+	return false, nil
+}
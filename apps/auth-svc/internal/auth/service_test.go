@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormLocale(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"", "en"},
+		{"ru", "ru"},
+		{"ru-RU", "ru"},
+		{" UK_ua ", "uk"},
+		{"en-GB", "en"},
+		{"de-DE", "en"},
+		{"*", "en"},
+		{"-ru", "en"},
+		{strings.Repeat("x", 64), "en"},
+		{"ru" + strings.Repeat("x", 30), "en"},
+	} {
+		if got := normLocale(tc.in); got != tc.want {
+			t.Errorf("normLocale(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+
+	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/repo"
+	"github.com/hassiimykyta/life-rpg/pkg/grpcserver"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+	userv1 "github.com/hassiimykyta/life-rpg/services/user/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// UserService — профиль пользователя поверх identity. Регистрация идёт через
+// AuthService.Register, поэтому CreateUser не реализован.
+type UserService struct {
+	userv1.UnimplementedUserServiceServer
+	repo *repo.IdentityRepo
+}
+
+func NewUserService(r *repo.IdentityRepo) *UserService {
+	return &UserService{repo: r}
+}
+
+// GetUser отдаёт профиль владельцу и сервисам.
+func (s *UserService) GetUser(ctx context.Context, in *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	id, err := ulid.UserIDFromProto(in.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if !canRead(ctx, id.String()) {
+		return nil, errAccessDenied
+	}
+	u, err := s.find(ctx, s.repo.FindByUserID, id.String())
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.GetUserResponse{User: u}, nil
+}
+
+// GetUserByEmail — как GetUser. Пользователю чужой или несуществующий email
+// одинаково даёт PermissionDenied, иначе по ответам можно перебирать адреса.
+func (s *UserService) GetUserByEmail(ctx context.Context, in *userv1.GetUserByEmailRequest) (*userv1.GetUserByEmailResponse, error) {
+	email := normIdentifier(in.GetEmail())
+	u, err := s.find(ctx, s.repo.FindByEmail, email)
+	switch {
+	case status.Code(err) == codes.NotFound && grpcserver.CallerService(ctx) == "":
+		return nil, errAccessDenied
+	case err != nil:
+		return nil, err
+	case !canRead(ctx, u.GetId()):
+		return nil, errAccessDenied
+	}
+	return &userv1.GetUserByEmailResponse{User: u}, nil
+}
+
+// UpdateAvatar сохраняет ссылки на уже обработанные картинки; сами файлы
+// загружает и нарезает gateway. Менять можно только свой аватар.
+func (s *UserService) UpdateAvatar(ctx context.Context, in *userv1.UpdateAvatarRequest) (*userv1.UpdateAvatarResponse, error) {
+	id, err := ulid.UserIDFromProto(in.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	userID := id.String()
+	if sub := grpcserver.Subject(ctx); sub != userID {
+		return nil, errAccessDenied
+	}
+
+	avatars := make([]models.AvatarImage, 0, len(in.GetAvatars()))
+	for _, a := range in.GetAvatars() {
+		avatars = append(avatars, models.AvatarImage{Size: int(a.GetSize()), URL: a.GetUrl()})
+	}
+	sort.Slice(avatars, func(i, j int) bool { return avatars[i].Size < avatars[j].Size })
+
+	// профиль читаем до записи: после неё чтение с реплики может вернуть старые аватары
+	u, err := s.find(ctx, s.repo.FindByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.repo.UpdateAvatars(ctx, userID, avatars)
+	if err != nil {
+		slog.ErrorContext(ctx, "update avatars failed", logx.Err(err))
+		return nil, status.Error(codes.Internal, "update failed")
+	}
+	if !ok {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	previous := u.Avatars
+	u.Avatars = avatarsToProto(avatars)
+	return &userv1.UpdateAvatarResponse{User: u, Previous: previous}, nil
+}
+
+var errAccessDenied = status.Error(codes.PermissionDenied, "access denied")
+
+// canRead — профиль читает его владелец или сервис с сервисным токеном.
+func canRead(ctx context.Context, userID string) bool {
+	return grpcserver.CallerService(ctx) != "" || grpcserver.Subject(ctx) == userID
+}
+
+func (s *UserService) find(ctx context.Context, find func(context.Context, string) (models.Identity, error), key string) (*userv1.User, error) {
+	ide, err := find(ctx, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "lookup failed")
+	}
+	return userToProto(ide), nil
+}
+
+func userToProto(ide models.Identity) *userv1.User {
+	return &userv1.User{
+		Id:        ide.UserId,
+		Email:     ide.Email,
+		Username:  ide.Username,
+		CreatedAt: ide.CreatedAt.Unix(),
+		Avatars:   avatarsToProto(ide.Avatars),
+	}
+}
+
+func avatarsToProto(avatars []models.AvatarImage) []*userv1.AvatarImage {
+	out := make([]*userv1.AvatarImage, 0, len(avatars))
+	for _, a := range avatars {
+		out = append(out, &userv1.AvatarImage{Size: int32(a.Size), Url: a.URL})
+	}
+	return out
+}
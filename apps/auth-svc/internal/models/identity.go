@@ -7,7 +7,15 @@ type Identity struct {
 	Email        string    `gorm:"size:255;uniqueIndex;not null"`
 	Username     string    `gorm:"size:64;uniqueIndex;not null"`
 	PasswordHash string    `gorm:"not null"`
+	Locale       string    `gorm:"size:16;not null;default:'en'"`
 	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	// Avatars — ссылки на обработанные картинки, от меньшей к большей
+	Avatars []AvatarImage `gorm:"type:jsonb;serializer:json;not null;default:'[]'"`
+}
+
+type AvatarImage struct {
+	Size int    `json:"size"`
+	URL  string `json:"url"`
 }
 
 func (Identity) TableName() string { return "identity" }
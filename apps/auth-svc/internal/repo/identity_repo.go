@@ -4,9 +4,12 @@ import (
 	"context"
 
 	"github.com/hassiimykyta/life-rpg/apps/auth-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
 	"gorm.io/gorm"
 )
 
+// IdentityRepo: Find* вне транзакции читают с реплик (если заданы DB_REPLICA_DSNS),
+// Create всегда идёт в primary.
 type IdentityRepo struct {
 	db *gorm.DB
 }
@@ -14,23 +17,35 @@ type IdentityRepo struct {
 func NewIdentityRepo(db *gorm.DB) *IdentityRepo { return &IdentityRepo{db: db} }
 
 func (r *IdentityRepo) Create(ctx context.Context, id models.Identity) error {
-	return r.db.WithContext(ctx).Create(&id).Error
+	return db.FromContext(ctx, r.db).Create(&id).Error
 }
 
 func (r *IdentityRepo) FindByEmail(ctx context.Context, email string) (models.Identity, error) {
 	var m models.Identity
-	err := r.db.WithContext(ctx).First(&m, "email = ?", email).Error
+	err := db.FromContext(ctx, r.db).First(&m, "email = ?", email).Error
 	return m, err
 }
 
 func (r *IdentityRepo) FindByUsername(ctx context.Context, username string) (models.Identity, error) {
 	var m models.Identity
-	err := r.db.WithContext(ctx).First(&m, "username = ?", username).Error
+	err := db.FromContext(ctx, r.db).First(&m, "username = ?", username).Error
 	return m, err
 }
 
 func (r *IdentityRepo) FindByUserID(ctx context.Context, userID string) (models.Identity, error) {
 	var m models.Identity
-	err := r.db.WithContext(ctx).First(&m, "user_id = ?", userID).Error
+	err := db.FromContext(ctx, r.db).First(&m, "user_id = ?", userID).Error
 	return m, err
 }
+
+// UpdateAvatars заменяет аватары пользователя; false — пользователя нет.
+func (r *IdentityRepo) UpdateAvatars(ctx context.Context, userID string, avatars []models.AvatarImage) (bool, error) {
+	if avatars == nil {
+		avatars = []models.AvatarImage{}
+	}
+	res := db.FromContext(ctx, r.db).
+		Model(&models.Identity{UserId: userID}).
+		Select("avatars").
+		Updates(models.Identity{Avatars: avatars})
+	return res.RowsAffected > 0, res.Error
+}
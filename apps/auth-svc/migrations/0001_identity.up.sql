@@ -0,0 +1,16 @@
+-- IF NOT EXISTS: в базах, созданных ещё GORM AutoMigrate, таблица и индексы уже
+-- есть; колонки, которых там могло не быть, добавляются отдельным ALTER ниже
+CREATE TABLE IF NOT EXISTS identity (
+    user_id       varchar(36)  PRIMARY KEY,
+    email         varchar(255) NOT NULL,
+    username      varchar(64)  NOT NULL,
+    password_hash text         NOT NULL,
+    locale        varchar(16)  NOT NULL DEFAULT 'en',
+    created_at    timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_identity_email ON identity (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_identity_username ON identity (username);
+
+-- locale появилась позже таблицы: в старой базе CREATE выше пропущен
+ALTER TABLE identity ADD COLUMN IF NOT EXISTS locale varchar(16) NOT NULL DEFAULT 'en';
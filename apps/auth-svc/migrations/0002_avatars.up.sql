@@ -0,0 +1 @@
+ALTER TABLE identity ADD COLUMN IF NOT EXISTS avatars jsonb NOT NULL DEFAULT '[]'::jsonb;
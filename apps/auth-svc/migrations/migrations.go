@@ -0,0 +1,8 @@
+// Package migrations содержит версионированные SQL-миграции auth-svc.
+// Новая миграция — следующий номер и пара файлов NNNN_name.up.sql / NNNN_name.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -3,32 +3,30 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/app"
+	"github.com/hassiimykyta/life-rpg/pkg/config"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
 )
 
 func main() {
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		if err := config.Print(os.Stdout, app.ConfigOpts...); err != nil {
+			log.Fatalf("config: %v", err)
+		}
+		return
+	}
+
 	a, err := app.New()
 	if err != nil {
 		log.Fatalf("gateway init: %v", err)
 	}
 
-	a.Start()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
-
-	log.Println("⏳ gateway shutting down...")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	if err := a.Stop(ctx); err != nil {
-		log.Printf("gateway stop error: %v", err)
+	if err := a.Run(context.Background()); err != nil {
+		slog.Error("gateway stopped with error", logx.Err(err))
+		os.Exit(1)
 	}
-	log.Println("✅ gateway bye")
+	slog.Info("gateway stopped")
 }
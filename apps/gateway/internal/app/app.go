@@ -3,59 +3,211 @@ package app
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/avatar"
 	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/clients"
 	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/handlers"
 	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/router"
-	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/jwt"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/transcode"
 	"github.com/hassiimykyta/life-rpg/pkg/config"
+	"github.com/hassiimykyta/life-rpg/pkg/flags"
+	"github.com/hassiimykyta/life-rpg/pkg/health"
 	"github.com/hassiimykyta/life-rpg/pkg/helpers"
 	"github.com/hassiimykyta/life-rpg/pkg/httpserver"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
+	"github.com/hassiimykyta/life-rpg/pkg/lifecycle"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
+	"github.com/hassiimykyta/life-rpg/pkg/redisx"
+	"github.com/hassiimykyta/life-rpg/pkg/storage"
+	"github.com/hassiimykyta/life-rpg/pkg/tlsx"
+	"github.com/hassiimykyta/life-rpg/pkg/tracing"
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+	authv1 "github.com/hassiimykyta/life-rpg/services/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// apiBase — префикс роутера, куда монтируются транслируемые RPC
+	apiBase          = "/api/v1"
+	transcodeTimeout = 5 * time.Second
 )
 
 type App struct {
-	cfg     *config.Config
-	server  *httpserver.Server
-	cleanup func() error
+	cfg *config.Config
+	lc  *lifecycle.Runner
+}
+
+func corsOpts(c *config.CORSConfig) router.CORSOpts {
+	return router.CORSOpts{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
 }
 
-func initRouter(cfg *config.Config, cli *clients.Clients, jwtMgr *jwt.Manager) *chi.Mux {
+func initRouter(cli *clients.Clients, jwtMgr *jwt.Manager, cache *redisx.Cache, cors *router.CORS, ff *flags.Service, store storage.Storage, avatars *avatar.Processor) (*chi.Mux, error) {
+	var media http.Handler
+	if l, ok := store.(*storage.Local); ok {
+		media = l.Handler()
+	}
+
+	authH := handlers.NewAuthHandler(jwtMgr)
+	authT, err := transcode.New(cli.AuthConn, authv1.AuthService_ServiceDesc.ServiceName, transcode.Options{
+		Base:    apiBase,
+		Timeout: transcodeTimeout,
+		Methods: authH.Methods(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return router.New(
 		router.Deps{
 			Handlers: router.Handlers{
-				AuthHandler: handlers.NewAuthHandler(cli.Auth, jwtMgr),
-			},
-		},
-		router.Options{
-			CORS: router.CORSOpts{
-				AllowedOrigins:   cfg.CORS.AllowedOrigins,
-				AllowedMethods:   cfg.CORS.AllowedMethods,
-				AllowedHeaders:   cfg.CORS.AllowedHeaders,
-				ExposedHeaders:   cfg.CORS.ExposedHeaders,
-				AllowCredentials: cfg.CORS.AllowCredentials,
-				MaxAge:           cfg.CORS.MaxAge,
+				AuthHandler:         authH,
+				NotificationHandler: handlers.NewNotificationHandler(cli.Notification),
+				MailWebhookHandler:  handlers.NewMailWebhookHandler(cli.Notification, jwtMgr, helpers.GetEnv("MAIL_WEBHOOK_SECRET", "")),
+				FlagsHandler:        handlers.NewFlagsHandler(ff),
+				UserHandler:         handlers.NewUserHandler(cli.User, avatars),
 			},
+			Jwt:        jwtMgr,
+			RDB:        cache,
+			Transcoded: []*transcode.Service{authT},
+			Media:      media,
 		},
-	)
+		router.Options{CORS: cors},
+	), nil
 }
 
 func New() (*App, error) {
-	cfg, err := config.Load(config.WithCORS(), config.WithJWT())
+	cfg, err := loadConfig()
 	if err != nil {
 		return nil, err
 	}
+	logx.Setup(logx.Options{Service: "gateway", Env: cfg.App.Env, Level: cfg.App.LogLevel, Format: cfg.App.LogFormat})
 
-	authAddr := helpers.GetEnv("AUTH_SVC_ADDR", "auth-svc:8081")
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		Service:     "gateway",
+		Env:         cfg.App.Env,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		certs *tlsx.Reloader
+		creds credentials.TransportCredentials
+	)
+	if cfg.TLS.Enabled {
+		certs, err = tlsx.NewReloader(tlsx.Options{
+			CertFile:       cfg.TLS.CertFile,
+			KeyFile:        cfg.TLS.KeyFile,
+			CAFile:         cfg.TLS.CAFile,
+			ReloadInterval: cfg.TLS.ReloadInterval,
+		})
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(certs.ClientConfig(cfg.TLS.AllowedPeers))
+	}
+
+	rdb, closeRedis, err := redisx.New(context.Background(), redisx.Config{
+		Mode:             cfg.Cache.Mode,
+		Addrs:            cfg.Cache.Addrs,
+		MasterName:       cfg.Cache.MasterName,
+		Username:         cfg.Cache.Username,
+		Password:         cfg.Cache.Password,
+		SentinelPassword: cfg.Cache.SentinelPassword,
+		DB:               cfg.Cache.DB,
+		DialTimeout:      cfg.Cache.DialTimeout,
+		ReadTimeout:      cfg.Cache.ReadTimeout,
+		WriteTimeout:     cfg.Cache.WriteTimeout,
+		PoolSize:         cfg.Cache.PoolSize,
+		MinIdleConns:     cfg.Cache.MinIdleConns,
+		TLSEnabled:       cfg.Cache.TLSEnabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cache := redisx.NewCache(rdb, "gateway:")
+
+	cli, cleanup, err := clients.NewClients(clients.Addrs{
+		Auth:         helpers.GetEnv("AUTH_SVC_ADDR", "auth-svc:8081"),
+		Notification: helpers.GetEnv("NOTIFICATION_SVC_ADDR", "notification-svc:8082"),
+	}, creds)
+	if err != nil {
+		_ = closeRedis()
+		return nil, err
+	}
+
+	flagsFile := helpers.GetEnv("FLAGS_FILE", "")
+	ff, err := loadFlags(flagsFile)
+	if err != nil {
+		_ = cleanup()
+		_ = closeRedis()
+		return nil, err
+	}
 
-	cli, cleanup, err := clients.NewClients(authAddr)
+	store, err := storage.New(context.Background(), storage.Config{
+		Driver:        cfg.Storage.Driver,
+		PublicBaseURL: cfg.Storage.PublicBaseURL,
+		PresignTTL:    cfg.Storage.PresignTTL,
+		LocalDir:      cfg.Storage.LocalDir,
+		SigningKey:    cfg.Storage.SigningKey,
+		// превью аватаров отдаются по PublicURL без подписи
+		LocalPublicPrefixes: []string{avatar.PublicPrefix},
+		S3Bucket:            cfg.Storage.S3Bucket,
+		S3Region:            cfg.Storage.S3Region,
+		S3Endpoint:          cfg.Storage.S3Endpoint,
+		S3AccessKey:         cfg.Storage.S3AccessKey,
+		S3SecretKey:         cfg.Storage.S3SecretKey,
+		S3UsePath:           cfg.Storage.S3UsePath,
+	})
 	if err != nil {
+		_ = cleanup()
+		_ = closeRedis()
 		return nil, err
 	}
+	avatars := avatar.NewProcessor(store, ulid.NewULIDGenerator(), cfg.Storage.PresignTTL)
 
 	jwtMgr := jwt.NewManager(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL)
-	r := initRouter(cfg, cli, jwtMgr)
+	cors := router.NewCORS(corsOpts(cfg.CORS))
+	r, err := initRouter(cli, jwtMgr, cache, cors, ff, store, avatars)
+	if err != nil {
+		_ = cleanup()
+		_ = closeRedis()
+		return nil, err
+	}
+
+	watcher := config.NewWatcher(cfg, ConfigOpts...)
+	watcher.WatchFile(flagsFile)
+	watcher.OnReload(func(_, cur *config.Config) {
+		logx.SetLevel(cur.App.LogLevel)
+		cors.Update(corsOpts(cur.CORS))
+		jwtMgr.SetTTL(cur.JWT.AccessTTL, cur.JWT.RefreshTTL)
+		if flagsFile == "" {
+			return
+		}
+		// битый файл флагов не сбрасывает текущие значения
+		next, err := flags.Load(flagsFile)
+		if err != nil {
+			slog.Error("flags reload failed", "file", flagsFile, logx.Err(err))
+			return
+		}
+		ff.Replace(next)
+	})
 
 	addr := fmt.Sprintf("%s:%s", cfg.App.Host, cfg.App.Port)
 	srv, err := httpserver.New(httpserver.Options{
@@ -67,24 +219,83 @@ func New() (*App, error) {
 	})
 	if err != nil {
 		_ = cleanup()
+		_ = closeRedis()
 		return nil, err
 	}
 
-	return &App{
-		cfg:     cfg,
-		server:  srv,
-		cleanup: cleanup,
-	}, nil
-}
+	checker := health.New(health.Options{})
+	// backend-сервисы — не повод снимать gateway с балансировки: запросы к
+	// упавшему сервису и так получат 502, остальные маршруты работают
+	cli.Registry.Each(func(name string, conn *grpc.ClientConn) {
+		checker.AddDependency(name, health.GRPC(conn))
+	})
+	// лимитер при недоступном Redis пропускает запросы, так что на readiness он не влияет
+	checker.AddDependency("redis", cache.Ping)
+	if s3, ok := store.(*storage.S3); ok {
+		checker.Add("storage", s3.Ping)
+	}
 
-func (a *App) Start() {
-	log.Printf("▶ gateway listening on http://%s:%s (env=%s)", a.cfg.App.Host, a.cfg.App.Port, a.cfg.App.Env)
-	a.server.Start()
+	admin, err := metrics.NewAdminServer(":"+cfg.App.AdminPort, checker.Mount)
+	if err != nil {
+		_ = cleanup()
+		_ = closeRedis()
+		return nil, err
+	}
+
+	lc := lifecycle.New(lifecycle.Options{
+		ShutdownTimeout: cfg.App.ShutdownTimeout,
+		DrainDelay:      cfg.App.DrainDelay,
+		Drain:           checker.Shutdown,
+	})
+	// gRPC-клиенты закрываются только после HTTP: запросы в обработке ещё ходят в сервисы
+	lc.Append(
+		lifecycle.Hook{Name: "tracing", Stop: shutdownTracing},
+		lifecycle.Hook{Name: "redis", Stop: func(context.Context) error { return closeRedis() }},
+		lifecycle.Hook{Name: "grpc clients", Stop: func(context.Context) error { return cleanup() }},
+	)
+	if certs != nil {
+		lc.Go("tls reloader", func(ctx context.Context) error { certs.Run(ctx); return nil })
+	}
+	lc.Go("config watcher", func(ctx context.Context) error { watcher.Run(ctx, configReloadInterval); return nil })
+	lc.Append(
+		lifecycle.Hook{Name: "health", Start: func(ctx context.Context) error { checker.Start(ctx); return nil }},
+		lifecycle.Hook{
+			Name:  "admin",
+			Start: func(context.Context) error { admin.Start(); return nil },
+			Stop:  admin.Shutdown,
+		},
+		lifecycle.Hook{
+			Name: "http",
+			Start: func(context.Context) error {
+				srv.Start()
+				slog.Info("gateway listening", "addr", addr, "admin_port", cfg.App.AdminPort, "env", cfg.App.Env)
+				return nil
+			},
+			Stop: srv.Shutdown,
+		},
+	)
+
+	// упавший Serve (не Shutdown) останавливает сервис, а не оставляет его без порта
+	lc.Watch("admin", admin.Err())
+	lc.Watch("http", srv.Err())
+
+	return &App{cfg: cfg, lc: lc}, nil
 }
 
-func (a *App) Stop(ctx context.Context) error {
-	if a.cleanup != nil {
-		_ = a.cleanup()
+// loadFlags читает FLAGS_FILE; без файла все флаги выключены.
+func loadFlags(path string) (*flags.Service, error) {
+	if path == "" {
+		return flags.New(nil), nil
+	}
+	f, err := flags.Load(path)
+	if err != nil {
+		return nil, err
 	}
-	return a.server.Shutdown(ctx)
+	return flags.New(f), nil
+}
+
+// Run работает до SIGINT/SIGTERM; HTTP дренируется раньше, чем закрываются
+// gRPC-клиенты и Redis.
+func (a *App) Run(ctx context.Context) error {
+	return a.lc.Run(ctx)
 }
@@ -0,0 +1,220 @@
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	_ "image/gif"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/storage"
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	maxUploadSize = 5 << 20
+	// maxPixels — защита от «бомб»: маленький файл с огромными размерами картинки
+	maxPixels   = 25_000_000
+	jpegQuality = 85
+
+	uploadPrefix = "uploads/avatars/"
+	// PublicPrefix — готовые превью; читаются без подписи, исходники в uploadPrefix — нет
+	PublicPrefix = "avatars/"
+)
+
+// Sizes — стороны квадратных превью, от меньшей к большей.
+var Sizes = []int{64, 128, 256}
+
+var limits = storage.Limits{
+	MaxSize:      maxUploadSize,
+	AllowedTypes: []string{"image/jpeg", "image/png", "image/gif", "image/webp"},
+}
+
+var (
+	ErrBadUpload = errors.New("avatar: upload not found or not owned by user")
+	ErrBadImage  = errors.New("avatar: unsupported or corrupted image")
+)
+
+type Image struct {
+	Size int
+	URL  string
+}
+
+// Processor: клиент грузит оригинал по presigned-ссылке во временный ключ,
+// затем Process проверяет его, перекодирует (EXIF и прочие метаданные
+// при этом теряются) и кладёт квадратные превью Sizes.
+type Processor struct {
+	store     storage.Storage
+	ids       *ulid.ULIDGenerator
+	uploadTTL time.Duration
+}
+
+func NewProcessor(s storage.Storage, ids *ulid.ULIDGenerator, uploadTTL time.Duration) *Processor {
+	return &Processor{store: s, ids: ids, uploadTTL: uploadTTL}
+}
+
+// Presign выдаёт ссылку на загрузку оригинала; ключ потом передаётся в Process.
+func (p *Processor) Presign(ctx context.Context, userID string, meta storage.Meta) (string, storage.Presigned, error) {
+	if err := limits.Check(meta); err != nil {
+		return "", storage.Presigned{}, err
+	}
+	id, err := p.ids.New()
+	if err != nil {
+		return "", storage.Presigned{}, err
+	}
+	key := uploadPrefix + userID + "/" + id
+	ps, err := p.store.PresignPut(ctx, key, meta, p.uploadTTL)
+	if err != nil {
+		return "", storage.Presigned{}, err
+	}
+	return key, ps, nil
+}
+
+// Process превращает загруженный оригинал в превью; сам оригинал удаляется.
+func (p *Processor) Process(ctx context.Context, userID, uploadKey string) ([]Image, error) {
+	if !strings.HasPrefix(uploadKey, uploadPrefix+userID+"/") {
+		return nil, ErrBadUpload
+	}
+
+	rc, obj, err := p.store.Open(ctx, uploadKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrBadUpload
+	}
+	if err != nil {
+		return nil, err
+	}
+	// оригинал одноразовый: после обработки, удачной или нет, он не нужен
+	defer func() { _ = p.store.Delete(context.WithoutCancel(ctx), uploadKey) }()
+	defer rc.Close()
+
+	if err := limits.Check(storage.Meta{ContentType: obj.ContentType, Size: obj.Size}); err != nil {
+		return nil, err
+	}
+
+	// тип определяется по содержимому, заявленный при загрузке не в счёт
+	r, _, err := limits.Reader(rc, "")
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	src, orientation, err := decode(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := p.ids.New()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Image, 0, len(Sizes))
+	for _, size := range Sizes {
+		// поворот после кадрирования: центральный квадрат от него не зависит,
+		// а крутить превью дешевле, чем оригинал
+		img, err := p.putSize(ctx, userID, version, size, orient(square(src, size), orientation))
+		if err != nil {
+			p.Remove(context.WithoutCancel(ctx), userID, out...)
+			return nil, err
+		}
+		out = append(out, img)
+	}
+	return out, nil
+}
+
+// Remove удаляет файлы превью, например прежней версии после UpdateAvatar.
+// Чужие и посторонние ссылки пропускаются; ошибки удаления не критичны.
+func (p *Processor) Remove(ctx context.Context, userID string, images ...Image) {
+	for _, img := range images {
+		key, ok := p.keyOf(userID, img.URL)
+		if !ok {
+			continue
+		}
+		if err := p.store.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "delete avatar failed", "key", key, logx.Err(err))
+		}
+	}
+}
+
+// keyOf восстанавливает ключ по ссылке из PublicURL; ссылки старого
+// формата (без версии в пути, с ?v=) тоже подходят.
+func (p *Processor) keyOf(userID, url string) (string, bool) {
+	url, _, _ = strings.Cut(url, "?")
+	key, ok := strings.CutPrefix(url, p.store.PublicURL(""))
+	if !ok || !strings.HasPrefix(key, PublicPrefix+userID+"/") {
+		return "", false
+	}
+	return key, true
+}
+
+// putSize кодирует превью: непрозрачные — в JPEG, с прозрачностью — в PNG.
+// Каждая версия пишется под своим ключом, поэтому CDN не отдаст старую картинку.
+func (p *Processor) putSize(ctx context.Context, userID, version string, size int, img image.Image) (Image, error) {
+	var buf bytes.Buffer
+	ext, ct := "png", "image/png"
+	if opaque(img) {
+		ext, ct = "jpg", "image/jpeg"
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return Image{}, err
+		}
+	} else if err := png.Encode(&buf, img); err != nil {
+		return Image{}, err
+	}
+
+	key := fmt.Sprintf("%s%s/%s/%d.%s", PublicPrefix, userID, version, size, ext)
+	if _, err := p.store.Put(ctx, key, &buf, storage.Meta{ContentType: ct, Size: int64(buf.Len())}); err != nil {
+		return Image{}, err
+	}
+	return Image{Size: size, URL: p.store.PublicURL(key)}, nil
+}
+
+// decode возвращает картинку и EXIF Orientation (1 — поворачивать не нужно).
+func decode(raw []byte) (image.Image, int, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrBadImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, 0, fmt.Errorf("%w: %dx%d", ErrBadImage, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrBadImage, err)
+	}
+	if format == "jpeg" {
+		return img, exifOrientation(raw), nil
+	}
+	return img, 1, nil
+}
+
+// square вырезает центральный квадрат и масштабирует его до size.
+func square(src image.Image, size int) *image.NRGBA {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const exifTagOrientation = 0x0112
+
+// exifOrientation достаёт тег Orientation (1..8) из APP1 JPEG; 1 — если тега нет.
+// Сами метаданные при перекодировании теряются, поэтому поворот нужно применить заранее.
+func exifOrientation(jpg []byte) int {
+	if len(jpg) < 4 || jpg[0] != 0xFF || jpg[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(jpg); {
+		if jpg[i] != 0xFF {
+			return 1
+		}
+		marker := jpg[i+1]
+		// SOS: дальше сжатые данные, метаданных уже не будет
+		if marker == 0xDA {
+			return 1
+		}
+		n := int(binary.BigEndian.Uint16(jpg[i+2:]))
+		if n < 2 || i+2+n > len(jpg) {
+			return 1
+		}
+		seg := jpg[i+4 : i+2+n]
+		if marker == 0xE1 && bytes.HasPrefix(seg, []byte("Exif\x00\x00")) {
+			return tiffOrientation(seg[6:])
+		}
+		i += 2 + n
+	}
+	return 1
+}
+
+func tiffOrientation(t []byte) int {
+	if len(t) < 8 {
+		return 1
+	}
+	var bo binary.ByteOrder
+	switch string(t[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return 1
+	}
+
+	// смещение сравнивается до приведения к int: на 32-битных платформах оно бы переполнилось
+	off32 := bo.Uint32(t[4:])
+	if uint64(off32)+2 > uint64(len(t)) {
+		return 1
+	}
+	ifd := int(off32)
+	count := int(bo.Uint16(t[ifd:]))
+	for e := 0; e < count; e++ {
+		off := ifd + 2 + e*12
+		if off+12 > len(t) {
+			return 1
+		}
+		if bo.Uint16(t[off:]) != exifTagOrientation {
+			continue
+		}
+		// тип SHORT, значение лежит прямо в поле value
+		if v := int(bo.Uint16(t[off+8:])); v >= 1 && v <= 8 {
+			return v
+		}
+		return 1
+	}
+	return 1
+}
+
+// orient поворачивает/отражает картинку так, чтобы она выглядела как
+// с учётом EXIF Orientation. Пиксели копируются прямо в Pix: вызывается
+// на готовых превью, но At/Set с интерфейсом color.Color всё равно в разы медленнее.
+func orient(src *image.NRGBA, o int) *image.NRGBA {
+	if o <= 1 || o > 8 {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch o {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			si := src.PixOffset(b.Min.X+sx, b.Min.Y+sy)
+			di := dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,145 @@
+package avatar
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// tiff собирает TIFF-заголовок с одним IFD из одной записи Orientation.
+func tiff(bo binary.ByteOrder, orientation uint16) []byte {
+	t := make([]byte, 8+2+12+4)
+	if bo == binary.LittleEndian {
+		copy(t, "II")
+	} else {
+		copy(t, "MM")
+	}
+	bo.PutUint16(t[2:], 42)
+	bo.PutUint32(t[4:], 8)
+	bo.PutUint16(t[8:], 1)
+	bo.PutUint16(t[10:], exifTagOrientation)
+	bo.PutUint16(t[12:], 3) // SHORT
+	bo.PutUint32(t[14:], 1)
+	bo.PutUint16(t[18:], orientation)
+	return t
+}
+
+// jpegWithApp1 — SOI, APP1 с payload и SOS; сжатых данных тесту не нужно.
+func jpegWithApp1(payload []byte) []byte {
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(payload)+2))
+	out = append(out, payload...)
+	return append(out, 0xFF, 0xDA, 0, 2)
+}
+
+func exif(t []byte) []byte {
+	return jpegWithApp1(append([]byte("Exif\x00\x00"), t...))
+}
+
+func TestExifOrientation(t *testing.T) {
+	hugeIFD := tiff(binary.BigEndian, 6)
+	binary.BigEndian.PutUint32(hugeIFD[4:], 0xFFFFFFFF)
+	hugeCount := tiff(binary.LittleEndian, 6)
+	binary.LittleEndian.PutUint16(hugeCount[8:], 0xFFFF)
+	binary.LittleEndian.PutUint16(hugeCount[10:], 0x0100) // ImageWidth: ищем дальше за концом
+	outOfRange := tiff(binary.LittleEndian, 9)
+
+	badLen := exif(tiff(binary.LittleEndian, 6))
+	binary.BigEndian.PutUint16(badLen[4:], 0xFFFF)
+	shortLen := exif(tiff(binary.LittleEndian, 6))
+	binary.BigEndian.PutUint16(shortLen[4:], 1)
+
+	for _, tc := range []struct {
+		name string
+		in   []byte
+		want int
+	}{
+		{"little endian", exif(tiff(binary.LittleEndian, 6)), 6},
+		{"big endian", exif(tiff(binary.BigEndian, 8)), 8},
+		{"empty", nil, 1},
+		{"not a jpeg", []byte("\x89PNG\r\n\x1a\n"), 1},
+		{"no exif", jpegWithApp1([]byte("http://ns.adobe.com/xap/1.0/\x00")), 1},
+		{"bad byte order", exif(append([]byte("XX"), tiff(binary.LittleEndian, 6)[2:]...)), 1},
+		{"ifd offset past end", exif(hugeIFD), 1},
+		{"entry count past end", exif(hugeCount), 1},
+		{"orientation out of range", exif(outOfRange), 1},
+		{"segment longer than file", badLen, 1},
+		{"segment length below 2", shortLen, 1},
+	} {
+		if got := exifOrientation(tc.in); got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExifOrientationTruncated(t *testing.T) {
+	full := exif(tiff(binary.BigEndian, 3))
+	for n := range len(full) {
+		if got := exifOrientation(full[:n]); got != 1 && got != 3 {
+			t.Fatalf("prefix %d: got %d", n, got)
+		}
+	}
+}
+
+func FuzzExifOrientation(f *testing.F) {
+	f.Add(exif(tiff(binary.LittleEndian, 6)))
+	f.Add(exif(tiff(binary.BigEndian, 8)))
+	f.Add([]byte{0xFF, 0xD8})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		if got := exifOrientation(b); got < 1 || got > 8 {
+			t.Fatalf("got %d", got)
+		}
+	})
+}
+
+// reference — эталон через At: куда пиксель (x, y) исходника попадает при Orientation o.
+func reference(src *image.NRGBA, o int) *image.NRGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := range h {
+		for x := range w {
+			var dx, dy int
+			switch o {
+			case 1:
+				dx, dy = x, y
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, src.At(x, y))
+		}
+	}
+	return dst
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for y := range 2 {
+		for x := range 3 {
+			src.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(10*y + x), A: 255})
+		}
+	}
+	for o := 1; o <= 8; o++ {
+		got, want := orient(src, o), reference(src, o)
+		if got.Bounds() != want.Bounds() || string(got.Pix) != string(want.Pix) {
+			t.Errorf("orientation %d: got %v %v, want %v %v", o, got.Bounds(), got.Pix, want.Bounds(), want.Pix)
+		}
+	}
+}
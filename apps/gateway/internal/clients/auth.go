@@ -0,0 +1,30 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// forwardToken кладёт access-токен пользователя в metadata authorization:
+// backend-сервисы сами проверяют, чьи данные запрашиваются.
+func forwardToken(ctx context.Context) context.Context {
+	if t := middleware.AccessToken(ctx); t != "" {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+t)
+	}
+	return ctx
+}
+
+func forwardTokenUnary() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(forwardToken(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func forwardTokenStream() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(forwardToken(ctx), desc, cc, method, opts...)
+	}
+}
@@ -1,58 +1,102 @@
 package clients
 
 import (
-	"context"
-	"fmt"
-	"log"
 	"time"
 
+	"github.com/hassiimykyta/life-rpg/pkg/grpcclient"
+	"github.com/hassiimykyta/life-rpg/pkg/helpers"
 	authv1 "github.com/hassiimykyta/life-rpg/services/auth/v1"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	userv1 "github.com/hassiimykyta/life-rpg/services/user/v1"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defaultTimeout   = 5 * time.Second
+	breakerThreshold = 5
+	breakerCooldown  = 10 * time.Second
 )
 
 type Clients struct {
-	Auth authv1.AuthServiceClient
+	Auth         authv1.AuthServiceClient
+	User         userv1.UserServiceClient
+	Notification notificationv1.NotificationServiceClient
+	// AuthConn — соединение auth-svc для transcode: вызовы по имени метода
+	AuthConn *grpc.ClientConn
+
+	// Registry — все соединения по имени сервиса (health-проверки, закрытие).
+	Registry *grpcclient.Registry
 }
 
-func NewClients(authAddr string) (*Clients, func() error, error) {
-	log.Printf("🔌 [gRPC] dialing %s ...", authAddr)
+// Addrs — адреса сервисов; через запятую можно перечислить несколько реплик.
+type Addrs struct {
+	Auth         string
+	Notification string
+}
 
-	conn, err := grpc.NewClient(
-		authAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+// NewClients не ждёт готовности сервисов: соединения ленивые, недоступный
+// сервис проявится ошибкой RPC и в /readyz, а не падением старта.
+// creds == nil — plaintext (dev без mTLS).
+func NewClients(addrs Addrs, creds credentials.TransportCredentials) (*Clients, func() error, error) {
+	reg := grpcclient.NewRegistry()
+
+	authConn, err := reg.Dial("auth-svc", options(addrs.Auth, creds, downstream{
+		// Register и CreateUser создают запись, UpdateAvatar удаляет прежний
+		// аватар — их повтор после обрыва ответа не безопасен
+		Idempotent: []string{
+			authv1.AuthService_Login_FullMethodName,
+			authv1.AuthService_CheckAvailability_FullMethodName,
+			authv1.AuthService_Resolve_FullMethodName,
+			userv1.UserService_GetUser_FullMethodName,
+			userv1.UserService_GetUserByEmail_FullMethodName,
+		},
+	}))
 	if err != nil {
-		log.Printf("❌ [gRPC] create client failed: %v", err)
 		return nil, nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	conn.Connect()
-
-	for {
-		s := conn.GetState()
-		if s == connectivity.Ready {
-			break
-		}
-		log.Printf("⏳ [gRPC] waiting for state: %s", s)
-		if !conn.WaitForStateChange(ctx, s) {
-			_ = conn.Close()
-			return nil, nil, fmt.Errorf("gRPC connect timeout to %s (last state: %s)", authAddr, s)
-		}
+	notifConn, err := reg.Dial("notification-svc", options(addrs.Notification, creds, downstream{
+		// чтение, установка флагов и настроек; вебхук обрабатывается идемпотентно
+		Idempotent: []string{"/" + notificationv1.NotificationService_ServiceDesc.ServiceName + "/"},
+		Timeouts: map[string]time.Duration{
+			// SSE-стрим живёт, пока открыт браузер
+			notificationv1.NotificationService_StreamInbox_FullMethodName: 0,
+		},
+	}))
+	if err != nil {
+		_ = reg.Close()
+		return nil, nil, err
 	}
 
-	log.Printf("✅ [gRPC] connected to %s", authAddr)
+	return &Clients{
+		Auth:         authv1.NewAuthServiceClient(authConn),
+		User:         userv1.NewUserServiceClient(authConn),
+		Notification: notificationv1.NewNotificationServiceClient(notifConn),
+		AuthConn:     authConn,
+		Registry:     reg,
+	}, reg.Close, nil
+}
 
-	cleanup := func() error {
-		log.Printf("🔌 [gRPC] closing %s", authAddr)
-		return conn.Close()
-	}
+// downstream — особенности методов сервиса (см. grpcclient.Options).
+type downstream struct {
+	Idempotent []string
+	Timeouts   map[string]time.Duration
+}
 
-	return &Clients{
-		Auth: authv1.NewAuthServiceClient(conn),
-	}, cleanup, nil
+func options(addrs string, creds credentials.TransportCredentials, d downstream) grpcclient.Options {
+	return grpcclient.Options{
+		Addrs:          helpers.Csv(addrs),
+		Creds:          creds,
+		DefaultTimeout: defaultTimeout,
+		Timeouts:       d.Timeouts,
+		Retry:          grpcclient.DefaultRetry,
+		Idempotent:     d.Idempotent,
+		Breaker: grpcclient.BreakerOptions{
+			Threshold: breakerThreshold,
+			Cooldown:  breakerCooldown,
+		},
+		UnaryInterceptors:  []grpc.UnaryClientInterceptor{forwardTokenUnary()},
+		StreamInterceptors: []grpc.StreamClientInterceptor{forwardTokenStream()},
+	}
 }
@@ -0,0 +1,5 @@
+package dto
+
+type FlagsResponse struct {
+	Flags map[string]bool `json:"flags"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+type InboxItem struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+	Read      bool              `json:"read"`
+	CreatedAt int64             `json:"created_at"`
+	ReadAt    int64             `json:"read_at,omitempty"`
+}
+
+type InboxResponse struct {
+	Items         []InboxItem `json:"items"`
+	NextPageToken string      `json:"next_page_token,omitempty"`
+	UnreadCount   int64       `json:"unread_count"`
+}
+
+type MarkReadRequest struct {
+	IDs []string `json:"ids,omitempty"`
+	All bool     `json:"all,omitempty"`
+}
+
+type MarkReadResponse struct {
+	Updated     int64 `json:"updated"`
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type ChannelPreference struct {
+	Kind    string `json:"kind"`
+	Channel string `json:"channel"`
+	Enabled bool   `json:"enabled"`
+}
+
+type Preferences struct {
+	Timezone   string              `json:"timezone"`
+	QuietStart string              `json:"quiet_start,omitempty"`
+	QuietEnd   string              `json:"quiet_end,omitempty"`
+	DigestMode string              `json:"digest_mode"`
+	Channels   []ChannelPreference `json:"channels"`
+}
+
+type UnsubscribeResponse struct {
+	Email string `json:"email"`
+	Kind  string `json:"kind,omitempty"`
+}
+
+type MailEvent struct {
+	Type      string `json:"type"`
+	Email     string `json:"email"`
+	MessageID string `json:"message_id,omitempty"`
+	Permanent bool   `json:"permanent,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type MailWebhookRequest struct {
+	Events []MailEvent `json:"events"`
+}
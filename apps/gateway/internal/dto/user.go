@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/hassiimykyta/life-rpg/pkg/storage"
+
+type Avatar struct {
+	Size int32  `json:"size"`
+	URL  string `json:"url"`
+}
+
+type User struct {
+	ID        string   `json:"id"`
+	Email     string   `json:"email"`
+	Username  string   `json:"username"`
+	CreatedAt int64    `json:"created_at"`
+	Avatars   []Avatar `json:"avatars"`
+}
+
+type AvatarUploadRequest struct {
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// AvatarUploadResponse: клиент выполняет Upload как есть, затем шлёт UploadKey в PUT /users/me/avatar.
+type AvatarUploadResponse struct {
+	UploadKey string            `json:"upload_key"`
+	Upload    storage.Presigned `json:"upload"`
+}
+
+type SetAvatarRequest struct {
+	UploadKey string `json:"upload_key"`
+}
@@ -1,139 +1,74 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/dto"
-	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/jwt"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/transcode"
 	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
 	authv1 "github.com/hassiimykyta/life-rpg/services/auth/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
+// AuthHandler: RPC AuthService публикуются через transcode по аннотациям
+// google.api.http, здесь — только то, что gateway добавляет сам (выдача JWT).
 type AuthHandler struct {
-	Client authv1.AuthServiceClient
-	Jwt    *jwt.Manager
+	Jwt *jwt.Manager
 }
 
-func NewAuthHandler(client authv1.AuthServiceClient, jwt *jwt.Manager) *AuthHandler {
-	return &AuthHandler{Client: client, Jwt: jwt}
+func NewAuthHandler(jwt *jwt.Manager) *AuthHandler {
+	return &AuthHandler{Jwt: jwt}
 }
 
-func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	var req dto.RegisterRequest
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-
-	out, err := h.Client.Register(ctx, &authv1.RegisterRequest{
-		Email:    req.Email,
-		Username: req.Username,
-		Password: req.Password,
-	})
-	if err != nil {
-		resp.ERROR(w, r, "error", http.StatusBadRequest)
-		return
-	}
-
-	acc, accExp, ref, refExp, err := h.Jwt.IssuePair(out.UserId)
-	if err != nil {
-		resp.ERROR(w, r, "error creation token")
-		return
+// Methods — доработки транслируемых RPC AuthService.
+func (h *AuthHandler) Methods() map[string]transcode.Method {
+	return map[string]transcode.Method{
+		"Register": {
+			Request: func(r *http.Request, in proto.Message) error {
+				req := in.(*authv1.RegisterRequest)
+				if req.Locale == "" {
+					req.Locale = preferredLanguage(r)
+				}
+				return nil
+			},
+			Response: func(_ *http.Request, out proto.Message) (any, error) {
+				return h.issue(out.(*authv1.RegisterResponse).GetUserId())
+			},
+			Status:  http.StatusCreated,
+			Message: "account created",
+		},
+		"Login": {
+			// вход по user_id — только для сервисов
+			Request: func(_ *http.Request, in proto.Message) error {
+				switch in.(*authv1.LoginRequest).Subject.(type) {
+				case *authv1.LoginRequest_Email, *authv1.LoginRequest_Username:
+					return nil
+				}
+				return status.Error(codes.InvalidArgument, "must provide email or username")
+			},
+			Response: func(_ *http.Request, out proto.Message) (any, error) {
+				return h.issue(out.(*authv1.LoginResponse).GetUserId())
+			},
+		},
 	}
-
-	resp.OK(w, r, map[string]any{"token": dto.Token{
-		AccessToken:      acc,
-		ExpiresAt:        accExp,
-		RefreshToken:     ref,
-		RefreshExpiresAt: refExp,
-	}},
-		"account created",
-		http.StatusCreated,
-	)
 }
 
-func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	var req dto.LoginRequest
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
-		return
-	}
-
-	if req.Email == "" && req.Username == "" {
-		resp.ERROR(w, r, "must provide email or username", http.StatusBadRequest)
-		return
-
-	}
-
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-
-	loginReq := &authv1.LoginRequest{
-		Password: req.Password,
-	}
-	if req.Email != "" {
-		loginReq.Subject = &authv1.LoginRequest_Email{Email: req.Email}
-	} else if req.Username != "" {
-		loginReq.Subject = &authv1.LoginRequest_Username{Username: req.Username}
-	}
-
-	out, err := h.Client.Login(ctx, loginReq)
-	if err != nil {
-		resp.ERROR(w, r, "auth error", http.StatusBadRequest)
-		return
-	}
-
-	acc, accExp, ref, refExp, err := h.Jwt.IssuePair(out.UserId)
+func (h *AuthHandler) issue(userID string) (any, error) {
+	acc, accExp, ref, refExp, err := h.Jwt.IssuePair(userID)
 	if err != nil {
-		resp.ERROR(w, r, "error creation token")
-		return
+		return nil, status.Error(codes.Internal, "error creation token")
 	}
-
-	resp.OK(w, r, map[string]any{"token": dto.Token{
+	return map[string]any{"token": dto.Token{
 		AccessToken:      acc,
 		ExpiresAt:        accExp,
 		RefreshToken:     ref,
 		RefreshExpiresAt: refExp,
-	}},
-		"ok",
-	)
-}
-
-func (h *AuthHandler) Availability(w http.ResponseWriter, r *http.Request) {
-	var req dto.AvailabilityRequest
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
-		return
-	}
-
-	if req.Email == "" && req.Username == "" {
-		resp.ERROR(w, r, "email or username required", http.StatusBadRequest)
-		return
-	}
-	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
-	defer cancel()
-	out, err := h.Client.CheckAvailability(ctx, &authv1.CheckAvailabilityRequest{
-		Email: req.Email, Username: req.Username,
-	})
-
-	if err != nil {
-		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
-		return
-	}
-	resp.OK(w, r, dto.AvailabilityResponse{
-		EmailAvailable:    out.EmailAvailable,
-		UsernameAvailable: out.UsernameAvailable,
-	}, "ok")
+	}}, nil
 }
 
 func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
@@ -165,3 +100,12 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 		"ok",
 	)
 }
+
+func preferredLanguage(r *http.Request) string {
+	al := r.Header.Get("Accept-Language")
+	if al == "" {
+		return ""
+	}
+	first := strings.Split(al, ",")[0]
+	return strings.TrimSpace(strings.Split(first, ";")[0])
+}
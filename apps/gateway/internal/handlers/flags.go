@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/dto"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/middleware"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	"github.com/hassiimykyta/life-rpg/pkg/flags"
+)
+
+// FlagsHandler отдаёт клиенту флаги, вычисленные для текущего пользователя.
+type FlagsHandler struct {
+	Flags *flags.Service
+}
+
+func NewFlagsHandler(f *flags.Service) *FlagsHandler {
+	return &FlagsHandler{Flags: f}
+}
+
+func (h *FlagsHandler) List(w http.ResponseWriter, r *http.Request) {
+	resp.OK(w, r, dto.FlagsResponse{
+		Flags: h.Flags.Evaluate(middleware.UserID(r.Context())),
+	}, "ok")
+}
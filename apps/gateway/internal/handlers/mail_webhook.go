@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/dto"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	maxWebhookBody = 1 << 20
+	// serviceName — subject сервисного токена gateway
+	serviceName     = "gateway"
+	serviceTokenTTL = time.Minute
+	// webhookMaxAge — окно для X-Webhook-Timestamp, защита от повтора перехваченного запроса
+	webhookMaxAge = 5 * time.Minute
+)
+
+// MailWebhookHandler принимает bounce/complaint от почтового провайдера.
+// Подпись — HMAC-SHA256 от "<timestamp>.<body>": X-Webhook-Signature: sha256=<hex>,
+// X-Webhook-Timestamp: unix-секунды. Запросы старше webhookMaxAge отклоняются.
+// ReportDeliveryEvent закрыт для пользователей, поэтому вызывается с сервисным токеном.
+type MailWebhookHandler struct {
+	Client notificationv1.NotificationServiceClient
+	Jwt    *jwt.Manager
+	secret []byte
+}
+
+func NewMailWebhookHandler(client notificationv1.NotificationServiceClient, m *jwt.Manager, secret string) *MailWebhookHandler {
+	return &MailWebhookHandler{Client: client, Jwt: m, secret: []byte(secret)}
+}
+
+func (h *MailWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(body, r.Header.Get("X-Webhook-Timestamp"), r.Header.Get("X-Webhook-Signature"), time.Now()) {
+		resp.ERROR(w, r, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.MailWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.Jwt.IssueService(serviceName, serviceTokenTTL)
+	if err != nil {
+		resp.ERROR(w, r, "internal error")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	for _, e := range req.Events {
+		_, err := h.Client.ReportDeliveryEvent(ctx, &notificationv1.ReportDeliveryEventRequest{
+			Type:              e.Type,
+			Email:             e.Email,
+			ProviderMessageId: e.MessageID,
+			Permanent:         e.Permanent,
+			Reason:            e.Reason,
+		})
+		if err != nil {
+			// провайдер повторит вебхук целиком, обработка идемпотентна
+			resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+			return
+		}
+	}
+
+	resp.OK(w, r, nil, "ok")
+}
+
+func (h *MailWebhookHandler) verify(body []byte, timestamp, header string, now time.Time) bool {
+	if len(h.secret) == 0 {
+		return false
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > webhookMaxAge || age < -webhookMaxAge {
+		return false
+	}
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	m := hmac.New(sha256.New, h.secret)
+	m.Write([]byte(timestamp))
+	m.Write([]byte("."))
+	m.Write(body)
+	return hmac.Equal(got, m.Sum(nil))
+}
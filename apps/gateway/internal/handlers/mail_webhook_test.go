@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/dto"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/grpc"
+)
+
+type fakeNotification struct {
+	notificationv1.NotificationServiceClient
+	events []*notificationv1.ReportDeliveryEventRequest
+}
+
+func (f *fakeNotification) ReportDeliveryEvent(_ context.Context, in *notificationv1.ReportDeliveryEventRequest, _ ...grpc.CallOption) (*notificationv1.ReportDeliveryEventResponse, error) {
+	f.events = append(f.events, in)
+	return &notificationv1.ReportDeliveryEventResponse{}, nil
+}
+
+func sign(secret, ts, body string) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts + "." + body))
+	return "sha256=" + hex.EncodeToString(m.Sum(nil))
+}
+
+// code — статус из тела: resp всегда отвечает 200 и кладёт код в BasicResponse.
+func code(t *testing.T, rec *httptest.ResponseRecorder) int {
+	t.Helper()
+	var out dto.BasicResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out.Code
+}
+
+func TestMailWebhook(t *testing.T) {
+	const (
+		secret = "webhook-secret"
+		body   = `{"events":[{"type":"bounce","email":"a@example.com","permanent":true}]}`
+	)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+	// прежняя схема без метки времени больше не принимается
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(body))
+	bodyOnly := "sha256=" + hex.EncodeToString(m.Sum(nil))
+
+	for _, tc := range []struct {
+		name      string
+		ts, sig   string
+		want      int
+		delivered int
+	}{
+		{"valid", now, sign(secret, now, body), http.StatusOK, 1},
+		{"wrong secret", now, sign("other", now, body), http.StatusUnauthorized, 0},
+		{"body-only signature", now, bodyOnly, http.StatusUnauthorized, 0},
+		{"timestamp not signed", stale, sign(secret, now, body), http.StatusUnauthorized, 0},
+		{"stale", stale, sign(secret, stale, body), http.StatusUnauthorized, 0},
+		{"from the future", future, sign(secret, future, body), http.StatusUnauthorized, 0},
+		{"no timestamp", "", sign(secret, "", body), http.StatusUnauthorized, 0},
+		{"no signature", now, "", http.StatusUnauthorized, 0},
+	} {
+		cli := &fakeNotification{}
+		h := NewMailWebhookHandler(cli, jwt.NewManager("jwt-secret", "test", time.Minute, time.Hour), secret)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/mail", strings.NewReader(body))
+		req.Header.Set("X-Webhook-Timestamp", tc.ts)
+		req.Header.Set("X-Webhook-Signature", tc.sig)
+		rec := httptest.NewRecorder()
+		h.Handle(rec, req)
+
+		if got := code(t, rec); got != tc.want || len(cli.events) != tc.delivered {
+			t.Errorf("%s: got %d with %d events, want %d with %d", tc.name, got, len(cli.events), tc.want, tc.delivered)
+		}
+	}
+}
+
+func TestMailWebhookWithoutSecret(t *testing.T) {
+	cli := &fakeNotification{}
+	h := NewMailWebhookHandler(cli, nil, "")
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/mail", strings.NewReader("{}"))
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", sign("", ts, "{}"))
+	rec := httptest.NewRecorder()
+	h.Handle(rec, req)
+
+	if got := code(t, rec); got != http.StatusUnauthorized {
+		t.Fatalf("got %d, want 401", got)
+	}
+}
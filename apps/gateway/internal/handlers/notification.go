@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/dto"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/middleware"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const sseKeepAlive = 25 * time.Second
+
+type NotificationHandler struct {
+	Client notificationv1.NotificationServiceClient
+}
+
+func NewNotificationHandler(client notificationv1.NotificationServiceClient) *NotificationHandler {
+	return &NotificationHandler{Client: client}
+}
+
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	pageSize, _ := strconv.Atoi(q.Get("page_size"))
+	unreadOnly, _ := strconv.ParseBool(q.Get("unread"))
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	out, err := h.Client.ListInbox(ctx, &notificationv1.ListInboxRequest{
+		UserId:     middleware.UserID(r.Context()),
+		UnreadOnly: unreadOnly,
+		PageSize:   int32(pageSize),
+		PageToken:  q.Get("page_token"),
+	})
+	if err != nil {
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	items := make([]dto.InboxItem, 0, len(out.Items))
+	for _, it := range out.Items {
+		items = append(items, inboxItem(it))
+	}
+
+	resp.OK(w, r, dto.InboxResponse{
+		Items:         items,
+		NextPageToken: out.NextPageToken,
+		UnreadCount:   out.UnreadCount,
+	}, "ok")
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	var req dto.MarkReadRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
+		return
+	}
+	if !req.All && len(req.IDs) == 0 {
+		resp.ERROR(w, r, "ids or all required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	out, err := h.Client.MarkInboxRead(ctx, &notificationv1.MarkInboxReadRequest{
+		UserId: middleware.UserID(r.Context()),
+		Ids:    req.IDs,
+		All:    req.All,
+	})
+	if err != nil {
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	resp.OK(w, r, dto.MarkReadResponse{
+		Updated:     out.Updated,
+		UnreadCount: out.UnreadCount,
+	}, "ok")
+}
+
+// Stream — SSE-поток новых уведомлений пользователя.
+func (h *NotificationHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// стрим живёт дольше WriteTimeout сервера
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	stream, err := h.Client.StreamInbox(r.Context(), &notificationv1.StreamInboxRequest{
+		UserId: middleware.UserID(r.Context()),
+	})
+	if err != nil {
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	items := make(chan *notificationv1.InboxItem)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			it, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case items <- it:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(sseKeepAlive)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-errs:
+			return
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case it := <-items:
+			b, err := json.Marshal(inboxItem(it))
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: notification\ndata: %s\n\n", it.Id, b); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	out, err := h.Client.GetPreferences(ctx, &notificationv1.GetPreferencesRequest{
+		UserId: middleware.UserID(r.Context()),
+	})
+	if err != nil {
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	resp.OK(w, r, preferences(out.Preferences), "ok")
+}
+
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req dto.Preferences
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	in := &notificationv1.Preferences{
+		Timezone:   req.Timezone,
+		QuietStart: req.QuietStart,
+		QuietEnd:   req.QuietEnd,
+		DigestMode: req.DigestMode,
+	}
+	for _, c := range req.Channels {
+		in.Channels = append(in.Channels, &notificationv1.ChannelPreference{
+			Kind:    c.Kind,
+			Channel: c.Channel,
+			Enabled: c.Enabled,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	out, err := h.Client.UpdatePreferences(ctx, &notificationv1.UpdatePreferencesRequest{
+		UserId:      middleware.UserID(r.Context()),
+		Preferences: in,
+	})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			resp.ERROR(w, r, status.Convert(err).Message(), http.StatusBadRequest)
+			return
+		}
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	resp.OK(w, r, preferences(out.Preferences), "ok")
+}
+
+// Unsubscribe — one-click отписка по ссылке из письма (RFC 8058).
+// GET только проверяет токен, отписывает POST: почтовые сканеры ходят по ссылкам GET-ом.
+func (h *NotificationHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		resp.ERROR(w, r, "token required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	dryRun := r.Method == http.MethodGet
+	out, err := h.Client.Unsubscribe(ctx, &notificationv1.UnsubscribeRequest{
+		Token:  token,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			resp.ERROR(w, r, "invalid token", http.StatusBadRequest)
+			return
+		}
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	msg := "unsubscribed"
+	if dryRun {
+		msg = "confirm with POST"
+	}
+	resp.OK(w, r, dto.UnsubscribeResponse{Email: out.Email, Kind: out.Kind}, msg)
+}
+
+func preferences(p *notificationv1.Preferences) dto.Preferences {
+	out := dto.Preferences{
+		Timezone:   p.GetTimezone(),
+		QuietStart: p.GetQuietStart(),
+		QuietEnd:   p.GetQuietEnd(),
+		DigestMode: p.GetDigestMode(),
+		Channels:   make([]dto.ChannelPreference, 0, len(p.GetChannels())),
+	}
+	for _, c := range p.GetChannels() {
+		out.Channels = append(out.Channels, dto.ChannelPreference{
+			Kind:    c.GetKind(),
+			Channel: c.GetChannel(),
+			Enabled: c.GetEnabled(),
+		})
+	}
+	return out
+}
+
+func inboxItem(it *notificationv1.InboxItem) dto.InboxItem {
+	return dto.InboxItem{
+		ID:        it.Id,
+		Kind:      it.Kind,
+		Title:     it.Title,
+		Body:      it.Body,
+		Data:      it.Data,
+		Read:      it.Read,
+		CreatedAt: it.CreatedAt,
+		ReadAt:    it.ReadAt,
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/avatar"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/dto"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/middleware"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/storage"
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+	userv1 "github.com/hassiimykyta/life-rpg/services/user/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// avatarProcessTimeout — декодирование и нарезка крупного оригинала заметно дольше обычного RPC.
+const avatarProcessTimeout = 30 * time.Second
+
+type UserHandler struct {
+	Client  userv1.UserServiceClient
+	Avatars *avatar.Processor
+}
+
+func NewUserHandler(client userv1.UserServiceClient, avatars *avatar.Processor) *UserHandler {
+	return &UserHandler{Client: client, Avatars: avatars}
+}
+
+func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	out, err := h.Client.GetUser(ctx, &userv1.GetUserRequest{
+		Id: ulid.UserID(middleware.UserID(r.Context())).Proto(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			resp.ERROR(w, r, "user not found", http.StatusNotFound)
+			return
+		}
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+	resp.OK(w, r, user(out.User), "ok")
+}
+
+// AvatarUpload выдаёт presigned-ссылку для загрузки оригинала напрямую в хранилище.
+func (h *UserHandler) AvatarUpload(w http.ResponseWriter, r *http.Request) {
+	var req dto.AvatarUploadRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		resp.ERROR(w, r, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	key, up, err := h.Avatars.Presign(r.Context(), middleware.UserID(r.Context()), storage.Meta{
+		ContentType: req.ContentType,
+		Size:        req.Size,
+	})
+	switch {
+	case errors.Is(err, storage.ErrTooLarge), errors.Is(err, storage.ErrContentType):
+		resp.ERROR(w, r, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		slog.ErrorContext(r.Context(), "presign avatar upload failed", logx.Err(err))
+		resp.ERROR(w, r, "storage error")
+		return
+	}
+
+	resp.OK(w, r, dto.AvatarUploadResponse{UploadKey: key, Upload: up}, "ok")
+}
+
+// SetAvatar обрабатывает загруженный оригинал и сохраняет превью в профиле.
+func (h *UserHandler) SetAvatar(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetAvatarRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UploadKey == "" {
+		resp.ERROR(w, r, "upload_key required", http.StatusBadRequest)
+		return
+	}
+	userID := middleware.UserID(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), avatarProcessTimeout)
+	defer cancel()
+
+	images, err := h.Avatars.Process(ctx, userID, req.UploadKey)
+	switch {
+	case errors.Is(err, avatar.ErrBadUpload):
+		resp.ERROR(w, r, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, avatar.ErrBadImage), errors.Is(err, storage.ErrTooLarge), errors.Is(err, storage.ErrContentType):
+		resp.ERROR(w, r, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		slog.ErrorContext(ctx, "process avatar failed", logx.Err(err))
+		resp.ERROR(w, r, "storage error")
+		return
+	}
+
+	in := &userv1.UpdateAvatarRequest{UserId: ulid.UserID(userID).Proto()}
+	for _, img := range images {
+		in.Avatars = append(in.Avatars, &userv1.AvatarImage{Size: int32(img.Size), Url: img.URL})
+	}
+	out, err := h.Client.UpdateAvatar(ctx, in)
+	if err != nil {
+		// явный отказ — новая версия никому не нужна; при таймауте или
+		// недоступности профиль мог обновиться, файлы оставляем
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.Unauthenticated:
+			h.Avatars.Remove(context.WithoutCancel(ctx), userID, images...)
+		}
+		resp.ERROR(w, r, "bad gateway", http.StatusBadGateway)
+		return
+	}
+	previous := make([]avatar.Image, 0, len(out.GetPrevious()))
+	for _, a := range out.GetPrevious() {
+		previous = append(previous, avatar.Image{Size: int(a.GetSize()), URL: a.GetUrl()})
+	}
+	h.Avatars.Remove(context.WithoutCancel(ctx), userID, previous...)
+	resp.OK(w, r, user(out.User), "ok")
+}
+
+func user(u *userv1.User) dto.User {
+	out := dto.User{
+		ID:        u.GetId(),
+		Email:     u.GetEmail(),
+		Username:  u.GetUsername(),
+		CreatedAt: u.GetCreatedAt(),
+		Avatars:   make([]dto.Avatar, 0, len(u.GetAvatars())),
+	}
+	for _, a := range u.GetAvatars() {
+		out.Avatars = append(out.Avatars, dto.Avatar{Size: a.GetSize(), URL: a.GetUrl()})
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+)
+
+type (
+	userIDKey      struct{}
+	accessTokenKey struct{}
+)
+
+// Auth проверяет access-токен из "Authorization: Bearer ...".
+func Auth(m *jwt.Manager) func(http.Handler) http.Handler {
+	return auth(m, false)
+}
+
+// AuthStream — Auth для SSE: EventSource в браузере не умеет ставить заголовки,
+// поэтому токен принимается и из query access_token. Только для стримов —
+// на остальных маршрутах токен в URL попадал бы в логи и историю браузера.
+func AuthStream(m *jwt.Manager) func(http.Handler) http.Handler {
+	return auth(m, true)
+}
+
+func auth(m *jwt.Manager, allowQuery bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := bearerToken(r, allowQuery)
+			if token == "" {
+				resp.ERROR(w, r, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := m.VerifyAccess(token)
+			if err != nil {
+				resp.ERROR(w, r, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userIDKey{}, claims.UserID)
+			ctx = context.WithValue(ctx, accessTokenKey{}, token)
+			ctx = logx.WithUserID(ctx, claims.UserID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey{}).(string)
+	return id
+}
+
+// AccessToken — проверенный токен запроса; пробрасывается в backend-сервисы.
+func AccessToken(ctx context.Context) string {
+	t, _ := ctx.Value(accessTokenKey{}).(string)
+	return t
+}
+
+func bearerToken(r *http.Request, allowQuery bool) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if t, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(t)
+		}
+		return ""
+	}
+	if !allowQuery {
+		return ""
+	}
+	return r.URL.Query().Get("access_token")
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/redisx"
+)
+
+// RateLimit пропускает не больше limit запросов с одного IP за window; счётчик
+// общий для всех реплик gateway. IP берётся из RemoteAddr — за прокси его
+// выставляет chi RealIP. Если Redis недоступен, запрос пропускается: вход
+// не должен ломаться вместе с кэшем.
+func RateLimit(c *redisx.Cache, name string, limit int, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok, retry, err := c.Allow(r.Context(), name+":"+clientIP(r), limit, window)
+			if err != nil {
+				slog.WarnContext(r.Context(), "rate limit check failed", "limiter", name, logx.Err(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+				resp.ERROR(w, r, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/dto"
+	"github.com/hassiimykyta/life-rpg/pkg/redisx"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRateLimit(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer rdb.Close()
+
+	h := RateLimit(redisx.NewCache(rdb, "test:"), "auth", 2, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	call := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := range 2 {
+		if rec := call("10.0.0.1:1234"); rec.Code != http.StatusNoContent {
+			t.Fatalf("call %d rejected", i)
+		}
+	}
+	// другой порт того же IP — тот же клиент
+	rec := call("10.0.0.1:5678")
+	var body dto.BasicResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if body.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Fatalf("over limit: code %d, Retry-After %q", body.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := call("10.0.0.2:1234"); rec.Code != http.StatusNoContent {
+		t.Fatal("limit shared between clients")
+	}
+
+	// Redis недоступен — запросы проходят
+	mr.Close()
+	if rec := call("10.0.0.1:1234"); rec.Code != http.StatusNoContent {
+		t.Fatal("rejected while Redis is down")
+	}
+}
@@ -1,22 +1,67 @@
 package router
 
 import (
+	"time"
+
 	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
 	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/middleware"
 )
 
+const requestTimeout = 60 * time.Second
+
+// authRateLimit — попыток входа, регистрации и refresh с одного IP за authRateWindow.
+const (
+	authRateLimit  = 20
+	authRateWindow = time.Minute
+)
+
 func MountAPI(r *chi.Mux, d Deps) {
 	r.Route("/api", func(api chi.Router) {
 		r.Use(middleware.JSONMiddleware)
 
 		api.Route("/v1", func(v1 chi.Router) {
-			v1.Route("/auth", func(auth chi.Router) {
-				auth.Post("/register", d.Handlers.AuthHandler.Register)
-				auth.Post("/login", d.Handlers.AuthHandler.Login)
-				auth.Post("/refresh", d.Handlers.AuthHandler.Refresh)
-				auth.Post("/availability", d.Handlers.AuthHandler.Availability)
+			v1.Group(func(rest chi.Router) {
+				rest.Use(chimw.Timeout(requestTimeout))
+
+				rest.Group(func(auth chi.Router) {
+					auth.Use(middleware.RateLimit(d.RDB, "auth", authRateLimit, authRateWindow))
+					// register, login, availability — из аннотаций auth.proto
+					for _, t := range d.Transcoded {
+						t.Mount(auth)
+					}
+					auth.Post("/auth/refresh", d.Handlers.AuthHandler.Refresh)
+				})
+
+				rest.Get("/unsubscribe", d.Handlers.NotificationHandler.Unsubscribe)
+				rest.Post("/unsubscribe", d.Handlers.NotificationHandler.Unsubscribe)
+				rest.Post("/webhooks/mail", d.Handlers.MailWebhookHandler.Handle)
 			})
 
+			v1.With(middleware.Auth(d.Jwt), chimw.Timeout(requestTimeout)).
+				Get("/flags", d.Handlers.FlagsHandler.List)
+
+			v1.Route("/users/me", func(u chi.Router) {
+				u.Use(middleware.Auth(d.Jwt))
+				u.Use(chimw.Timeout(requestTimeout))
+				u.Get("/", d.Handlers.UserHandler.Me)
+				u.Post("/avatar/upload", d.Handlers.UserHandler.AvatarUpload)
+				u.Put("/avatar", d.Handlers.UserHandler.SetAvatar)
+			})
+
+			v1.Route("/notifications", func(n chi.Router) {
+				// SSE-стрим без таймаута запроса; токен может прийти в query
+				n.With(middleware.AuthStream(d.Jwt)).Get("/stream", d.Handlers.NotificationHandler.Stream)
+
+				n.Group(func(rest chi.Router) {
+					rest.Use(middleware.Auth(d.Jwt))
+					rest.Use(chimw.Timeout(requestTimeout))
+					rest.Get("/", d.Handlers.NotificationHandler.List)
+					rest.Post("/read", d.Handlers.NotificationHandler.MarkRead)
+					rest.Get("/preferences", d.Handlers.NotificationHandler.GetPreferences)
+					rest.Put("/preferences", d.Handlers.NotificationHandler.UpdatePreferences)
+				})
+			})
 		})
 	})
 }
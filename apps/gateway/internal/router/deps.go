@@ -1,15 +1,28 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/handlers"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/internal/transcode"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
 	"github.com/hassiimykyta/life-rpg/pkg/redisx"
 )
 
 type Handlers struct {
-	AuthHandler *handlers.AuthHandler
+	AuthHandler         *handlers.AuthHandler
+	NotificationHandler *handlers.NotificationHandler
+	MailWebhookHandler  *handlers.MailWebhookHandler
+	FlagsHandler        *handlers.FlagsHandler
+	UserHandler         *handlers.UserHandler
 }
 
 type Deps struct {
 	Handlers Handlers
+	Jwt      *jwt.Manager
 	RDB      *redisx.Cache
+	// Transcoded — RPC, опубликованные по аннотациям google.api.http (база /api/v1)
+	Transcoded []*transcode.Service
+	// Media — раздача и приём файлов локального хранилища; nil для S3
+	Media http.Handler
 }
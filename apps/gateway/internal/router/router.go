@@ -1,11 +1,15 @@
 package router
 
 import (
-	"time"
+	"net/http"
+	"sync/atomic"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	appmw "github.com/hassiimykyta/life-rpg/apps/gateway/internal/middleware"
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
+	"github.com/hassiimykyta/life-rpg/pkg/storage"
 )
 
 type CORSOpts struct {
@@ -17,29 +21,61 @@ type CORSOpts struct {
 	MaxAge           int
 }
 
+// CORS — middleware с подменяемыми настройками: конфиг перечитывается без рестарта.
+type CORS struct {
+	c atomic.Pointer[cors.Cors]
+}
+
+func NewCORS(opts CORSOpts) *CORS {
+	c := &CORS{}
+	c.Update(opts)
+	return c
+}
+
+func (c *CORS) Update(opts CORSOpts) {
+	c.c.Store(cors.New(cors.Options{
+		AllowedOrigins:   opts.AllowedOrigins,
+		AllowedMethods:   opts.AllowedMethods,
+		AllowedHeaders:   opts.AllowedHeaders,
+		ExposedHeaders:   opts.ExposedHeaders,
+		AllowCredentials: opts.AllowCredentials,
+		MaxAge:           opts.MaxAge,
+	}))
+}
+
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.c.Load().Handler(next).ServeHTTP(w, r)
+	})
+}
+
 type Options struct {
-	CORS CORSOpts
+	CORS *CORS
 }
 
 func New(d Deps, opts Options) *chi.Mux {
 	r := chi.NewRouter()
 
+	r.Use(appmw.Tracing)
+	r.Use(metrics.HTTPMiddleware(routePattern))
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(appmw.RequestLogger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
-
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   opts.CORS.AllowedOrigins,
-		AllowedMethods:   opts.CORS.AllowedMethods,
-		AllowedHeaders:   opts.CORS.AllowedHeaders,
-		ExposedHeaders:   opts.CORS.ExposedHeaders,
-		AllowCredentials: opts.CORS.AllowCredentials,
-		MaxAge:           opts.CORS.MaxAge,
-	}))
+
+	r.Use(opts.CORS.Handler)
 
 	MountAPI(r, d)
+	if d.Media != nil {
+		r.Handle(storage.LocalPrefix+"*", d.Media)
+	}
 
 	return r
 }
+
+func routePattern(r *http.Request) string {
+	if rc := chi.RouteContext(r.Context()); rc != nil {
+		return rc.RoutePattern()
+	}
+	return ""
+}
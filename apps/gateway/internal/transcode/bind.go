@@ -0,0 +1,144 @@
+package transcode
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// bind заполняет запрос: тело по правилу body, затем query (если тело не
+// забрало сообщение целиком) и переменные пути — они главнее всего остального.
+func bind(r *http.Request, rt route, in proto.Message) error {
+	msg := in.ProtoReflect()
+
+	if rt.body != "" {
+		raw, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodySize))
+		if err != nil {
+			return err
+		}
+		if len(raw) > 0 {
+			target := in
+			if rt.body != "*" {
+				fd := msg.Descriptor().Fields().ByName(protoreflect.Name(rt.body))
+				target = msg.Mutable(fd).Message().Interface()
+			}
+			if err := unmarshal.Unmarshal(raw, target); err != nil {
+				return err
+			}
+		}
+	}
+
+	if rt.body != "*" {
+		for key, vals := range r.URL.Query() {
+			fd, err := fieldByPath(msg.Descriptor(), key)
+			if err != nil {
+				// лишние параметры (utm и т.п.) не ошибка
+				continue
+			}
+			for _, v := range vals {
+				if err := setField(msg, key, fd, v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for name, path := range rt.vars {
+		fd, _ := fieldByPath(msg.Descriptor(), path)
+		if err := setField(msg, path, fd, chi.URLParam(r, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldByPath ищет поле по пути a.b.c; имена — как в proto или в JSON (camelCase).
+func fieldByPath(md protoreflect.MessageDescriptor, path string) (protoreflect.FieldDescriptor, error) {
+	var fd protoreflect.FieldDescriptor
+	for i, part := range strings.Split(path, ".") {
+		if i > 0 {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+				return nil, fmt.Errorf("field %s: %s is not a message", path, fd.Name())
+			}
+			md = fd.Message()
+		}
+		fields := md.Fields()
+		if fd = fields.ByName(protoreflect.Name(part)); fd == nil {
+			fd = fields.ByJSONName(part)
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("field %s: no %s in %s", path, part, md.FullName())
+		}
+	}
+	if fd.Kind() == protoreflect.MessageKind || fd.IsMap() {
+		return nil, fmt.Errorf("field %s: only scalar fields can be bound from URL", path)
+	}
+	return fd, nil
+}
+
+func setField(msg protoreflect.Message, path string, fd protoreflect.FieldDescriptor, raw string) error {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		fields := msg.Descriptor().Fields()
+		parent := fields.ByName(protoreflect.Name(part))
+		if parent == nil {
+			parent = fields.ByJSONName(part)
+		}
+		msg = msg.Mutable(parent).Message()
+	}
+
+	v, err := scalar(fd, raw)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", path, err)
+	}
+	if fd.IsList() {
+		msg.Mutable(fd).List().Append(v)
+		return nil
+	}
+	msg.Set(fd, v)
+	return nil
+}
+
+func scalar(fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(raw)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(raw, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(raw, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(raw, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(raw, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(raw)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown enum value %q", raw)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported kind %s", fd.Kind())
+}
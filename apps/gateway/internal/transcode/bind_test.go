@@ -0,0 +1,171 @@
+package transcode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// notificationMethod — дескриптор RPC NotificationService (он без аннотаций,
+// правила HTTP задаются в самих тестах).
+func notificationMethod(name string) protoreflect.MethodDescriptor {
+	return notificationv1.File_notification_v1_notification_proto.Services().
+		ByName("NotificationService").Methods().ByName(protoreflect.Name(name))
+}
+
+// bindRequest строит маршрут по rule и прогоняет запрос через bind под chi.
+func bindRequest(t *testing.T, method string, rule *annotations.HttpRule, verb, target, body string) (proto.Message, error) {
+	t.Helper()
+	rt, err := newRoute(notificationMethod(method), rule, Options{Base: "/api/v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := rt.in.New().Interface()
+	var bindErr error
+	r := chi.NewRouter()
+	r.Method(rt.verb, rt.pattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bindErr = bind(r, rt, in)
+	}))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(verb, strings.TrimPrefix(target, "/api/v1"), strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("route %s %s did not match %s: %d", rt.verb, rt.pattern, target, rec.Code)
+	}
+	return in, bindErr
+}
+
+func get(path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: path}}
+}
+
+func TestBindQueryAndPath(t *testing.T) {
+	in, err := bindRequest(t, "ListInbox", get("/api/v1/users/{user_id}/inbox"), http.MethodGet,
+		"/api/v1/users/u1/inbox?unread_only=true&pageSize=20&page_token=p2&user_id=evil&utm_source=mail", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := in.(*notificationv1.ListInboxRequest)
+	if req.GetUserId() != "u1" {
+		t.Errorf("user_id = %q, path variable must win over query", req.GetUserId())
+	}
+	if !req.GetUnreadOnly() || req.GetPageSize() != 20 || req.GetPageToken() != "p2" {
+		t.Errorf("query not bound: %v", req)
+	}
+}
+
+func TestBindRepeatedQuery(t *testing.T) {
+	rule := &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/api/v1/users/{user_id}/inbox/read"}}
+	in, err := bindRequest(t, "MarkInboxRead", rule, http.MethodPost, "/api/v1/users/u1/inbox/read?ids=a&ids=b", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := in.(*notificationv1.MarkInboxReadRequest).GetIds(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("ids = %v", got)
+	}
+}
+
+func TestBindBody(t *testing.T) {
+	t.Run("field body and nested path var", func(t *testing.T) {
+		rule := &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Put{Put: "/api/v1/users/{user_id}/preferences/{preferences.timezone}"},
+			Body:    "preferences",
+		}
+		in, err := bindRequest(t, "UpdatePreferences", rule, http.MethodPut,
+			"/api/v1/users/u1/preferences/UTC?user_id=evil", `{"digest_mode":"daily","timezone":"Europe/Kyiv"}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := in.(*notificationv1.UpdatePreferencesRequest)
+		p := req.GetPreferences()
+		if req.GetUserId() != "u1" || p.GetDigestMode() != "daily" || p.GetTimezone() != "UTC" {
+			t.Errorf("request = %v", req)
+		}
+	})
+
+	t.Run("star body ignores query", func(t *testing.T) {
+		rule := &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Post{Post: "/api/v1/users/{user_id}/inbox"},
+			Body:    "*",
+		}
+		in, err := bindRequest(t, "ListInbox", rule, http.MethodPost,
+			"/api/v1/users/u1/inbox?page_size=5", `{"page_size":10,"unread_only":true}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := in.(*notificationv1.ListInboxRequest)
+		if req.GetUserId() != "u1" || req.GetPageSize() != 10 || !req.GetUnreadOnly() {
+			t.Errorf("request = %v", req)
+		}
+	})
+}
+
+func TestBindErrors(t *testing.T) {
+	for _, target := range []string{
+		"/api/v1/users/u1/inbox?page_size=many",
+		"/api/v1/users/u1/inbox?unread_only=maybe",
+		"/api/v1/users/u1/inbox?page_size=99999999999",
+	} {
+		if _, err := bindRequest(t, "ListInbox", get("/api/v1/users/{user_id}/inbox"), http.MethodGet, target, ""); err == nil {
+			t.Errorf("%s: expected bind error", target)
+		}
+	}
+}
+
+func TestNewRouteErrors(t *testing.T) {
+	cases := []struct {
+		name, method string
+		rule         *annotations.HttpRule
+		want         string
+	}{
+		{"no pattern", "ListInbox", &annotations.HttpRule{}, "no HTTP pattern"},
+		{"unknown path field", "ListInbox", get("/api/v1/users/{owner}"), "no owner"},
+		{"message path field", "UpdatePreferences", get("/api/v1/p/{preferences}"), "only scalar fields"},
+		{"multi-segment var", "ListInbox", get("/api/v1/users/{user_id=**}"), "unsupported path variable"},
+		{"custom verb", "ListInbox", get("/api/v1/users/{user_id}:list"), "unsupported path segment"},
+		{"scalar body", "ListInbox", &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/api/v1/x"}, Body: "user_id"}, `body "user_id"`},
+		{"list response body", "ListInbox", &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/api/v1/x"}, ResponseBody: "items"}, `response_body "items"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newRoute(notificationMethod(tc.method), tc.rule, Options{Base: "/api/v1"})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestResponseBody(t *testing.T) {
+	rule := &annotations.HttpRule{
+		Pattern:      &annotations.HttpRule_Get{Get: "/api/v1/users/{user_id}/preferences"},
+		ResponseBody: "preferences",
+	}
+	rt, err := newRoute(notificationMethod("GetPreferences"), rule, Options{Base: "/api/v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &fakeConn{reply: func(out proto.Message) error {
+		out.(*notificationv1.GetPreferencesResponse).Preferences = &notificationv1.Preferences{Timezone: "UTC"}
+		return nil
+	}}
+	s := &Service{conn: conn, routes: []route{rt}}
+	r := chi.NewRouter()
+	s.Mount(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/u1/preferences", nil))
+	if !strings.Contains(rec.Body.String(), `"data":{"timezone":"UTC"`) {
+		t.Errorf("body = %s, want preferences unwrapped into data", rec.Body.String())
+	}
+	if conn.method != "/notification.v1.NotificationService/GetPreferences" {
+		t.Errorf("method = %q", conn.method)
+	}
+}
@@ -0,0 +1,269 @@
+package transcode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hassiimykyta/life-rpg/apps/gateway/pkg/resp"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const maxBodySize = 1 << 20
+
+var (
+	// snake_case, как в остальном API; нулевые значения не выкидываются
+	marshal   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	unmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// Method — доработка RPC, которому мало прямой трансляции JSON <-> proto.
+type Method struct {
+	// Request вызывается после разбора запроса: значения по умолчанию из
+	// заголовков, дополнительные проверки. Ошибка отдаётся как ошибка сервиса.
+	Request func(r *http.Request, in proto.Message) error
+	// Response подменяет data в ответе; по умолчанию — само сообщение
+	Response func(r *http.Request, out proto.Message) (any, error)
+	// Status и Message успешного ответа; по умолчанию 200 и "ok"
+	Status  int
+	Message string
+}
+
+type Options struct {
+	// Base — путь роутера, в который монтируются маршруты (например /api/v1);
+	// пути в аннотациях указываются полностью и должны с него начинаться
+	Base string
+	// Timeout — на один вызов; 0 — остаётся дефолтный таймаут клиента
+	Timeout time.Duration
+	// Methods — по короткому имени RPC ("Register")
+	Methods map[string]Method
+}
+
+type route struct {
+	verb    string
+	pattern string
+	rpc     string
+	in, out protoreflect.MessageType
+	// body — "*", имя поля или пусто (всё из query)
+	body string
+	// vars — параметр chi -> путь поля в запросе
+	vars     map[string]string
+	respBody protoreflect.FieldDescriptor
+	method   Method
+}
+
+// Service публикует RPC сервиса по HTTP согласно аннотациям google.api.http.
+// Ответы и ошибки идут в обычном конверте BasicResponse.
+type Service struct {
+	conn    grpc.ClientConnInterface
+	timeout time.Duration
+	routes  []route
+}
+
+// New строит маршруты для сервиса service (полное имя, например auth.v1.AuthService).
+// Пакет со сгенерированным кодом сервиса должен быть импортирован.
+func New(conn grpc.ClientConnInterface, service string, opts Options) (*Service, error) {
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("transcode: %s: %w", service, err)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("transcode: %s is not a service", service)
+	}
+
+	s := &Service{conn: conn, timeout: opts.Timeout}
+	known := map[string]bool{}
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		known[string(md.Name())] = true
+
+		rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		if md.IsStreamingClient() || md.IsStreamingServer() {
+			return nil, fmt.Errorf("transcode: %s: streaming methods are not supported", md.FullName())
+		}
+		for _, b := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			rt, err := newRoute(md, b, opts)
+			if err != nil {
+				return nil, fmt.Errorf("transcode: %s: %w", md.FullName(), err)
+			}
+			s.routes = append(s.routes, rt)
+		}
+	}
+	for name := range opts.Methods {
+		if !known[name] {
+			return nil, fmt.Errorf("transcode: %s has no method %s", service, name)
+		}
+	}
+	return s, nil
+}
+
+func newRoute(md protoreflect.MethodDescriptor, rule *annotations.HttpRule, opts Options) (route, error) {
+	in, err := protoregistry.GlobalTypes.FindMessageByName(md.Input().FullName())
+	if err != nil {
+		return route{}, err
+	}
+	out, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return route{}, err
+	}
+
+	rt := route{
+		rpc:    fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name()),
+		in:     in,
+		out:    out,
+		body:   rule.GetBody(),
+		method: opts.Methods[string(md.Name())],
+	}
+
+	var path string
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		rt.verb, path = http.MethodGet, p.Get
+	case *annotations.HttpRule_Post:
+		rt.verb, path = http.MethodPost, p.Post
+	case *annotations.HttpRule_Put:
+		rt.verb, path = http.MethodPut, p.Put
+	case *annotations.HttpRule_Patch:
+		rt.verb, path = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Delete:
+		rt.verb, path = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Custom:
+		rt.verb, path = strings.ToUpper(p.Custom.GetKind()), p.Custom.GetPath()
+	default:
+		return route{}, fmt.Errorf("no HTTP pattern")
+	}
+
+	rel, ok := strings.CutPrefix(path, opts.Base)
+	if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+		return route{}, fmt.Errorf("path %s is outside %s", path, opts.Base)
+	}
+	rt.pattern, rt.vars, err = chiPattern(rel, in.Descriptor())
+	if err != nil {
+		return route{}, err
+	}
+
+	if rt.body != "" && rt.body != "*" {
+		fd := in.Descriptor().Fields().ByName(protoreflect.Name(rt.body))
+		if fd == nil || fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return route{}, fmt.Errorf("body %q must be a message field", rt.body)
+		}
+	}
+	if rb := rule.GetResponseBody(); rb != "" {
+		fd := out.Descriptor().Fields().ByName(protoreflect.Name(rb))
+		if fd == nil || fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return route{}, fmt.Errorf("response_body %q must be a message field", rb)
+		}
+		rt.respBody = fd
+	}
+	return rt, nil
+}
+
+// chiPattern переводит шаблон вида /users/{id.value} в /users/{id_value}.
+// Поддерживаются только переменные на один сегмент пути.
+func chiPattern(path string, md protoreflect.MessageDescriptor) (string, map[string]string, error) {
+	vars := map[string]string{}
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if !strings.HasPrefix(seg, "{") {
+			if strings.ContainsAny(seg, "{}*:") {
+				return "", nil, fmt.Errorf("unsupported path segment %q", seg)
+			}
+			continue
+		}
+		field, ok := strings.CutSuffix(seg[1:], "}")
+		if !ok {
+			return "", nil, fmt.Errorf("unsupported path segment %q", seg)
+		}
+		if f, tpl, ok := strings.Cut(field, "="); ok {
+			if tpl != "*" {
+				return "", nil, fmt.Errorf("unsupported path variable %q", seg)
+			}
+			field = f
+		}
+		if _, err := fieldByPath(md, field); err != nil {
+			return "", nil, err
+		}
+		name := strings.ReplaceAll(field, ".", "_")
+		vars[name] = field
+		segs[i] = "{" + name + "}"
+	}
+	return strings.Join(segs, "/"), vars, nil
+}
+
+// Mount регистрирует маршруты в роутере, смонтированном по Options.Base.
+func (s *Service) Mount(r chi.Router) {
+	for _, rt := range s.routes {
+		r.Method(rt.verb, rt.pattern, s.handler(rt))
+	}
+}
+
+func (s *Service) handler(rt route) http.HandlerFunc {
+	code, message := http.StatusOK, "ok"
+	if rt.method.Status != 0 {
+		code = rt.method.Status
+	}
+	if rt.method.Message != "" {
+		message = rt.method.Message
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := rt.in.New().Interface()
+		if err := bind(r, rt, in); err != nil {
+			resp.ERROR(w, r, "bad request", http.StatusBadRequest)
+			return
+		}
+		if rt.method.Request != nil {
+			if err := rt.method.Request(r, in); err != nil {
+				resp.GRPC(w, r, err)
+				return
+			}
+		}
+
+		ctx := r.Context()
+		if s.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+		}
+
+		out := rt.out.New().Interface()
+		if err := s.conn.Invoke(ctx, rt.rpc, in, out); err != nil {
+			resp.GRPC(w, r, err)
+			return
+		}
+
+		if rt.method.Response != nil {
+			data, err := rt.method.Response(r, out)
+			if err != nil {
+				resp.GRPC(w, r, err)
+				return
+			}
+			resp.OK(w, r, data, message, code)
+			return
+		}
+
+		if rt.respBody != nil {
+			out = out.ProtoReflect().Get(rt.respBody).Message().Interface()
+		}
+		raw, err := marshal.Marshal(out)
+		if err != nil {
+			resp.ERROR(w, r, "encode error")
+			return
+		}
+		resp.OK(w, r, json.RawMessage(raw), message, code)
+	}
+}
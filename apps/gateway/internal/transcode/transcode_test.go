@@ -0,0 +1,204 @@
+package transcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	authv1 "github.com/hassiimykyta/life-rpg/services/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeConn отвечает на Invoke через reply и запоминает вызов.
+type fakeConn struct {
+	method   string
+	in       proto.Message
+	deadline bool
+	reply    func(out proto.Message) error
+}
+
+func (f *fakeConn) Invoke(ctx context.Context, method string, in, out any, _ ...grpc.CallOption) error {
+	f.method, f.in = method, proto.Clone(in.(proto.Message))
+	_, f.deadline = ctx.Deadline()
+	if f.reply == nil {
+		return nil
+	}
+	return f.reply(out.(proto.Message))
+}
+
+func (f *fakeConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("not supported")
+}
+
+type envelope struct {
+	Code    int             `json:"code"`
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+}
+
+func newAuth(t *testing.T, conn *fakeConn, opts Options) http.Handler {
+	t.Helper()
+	opts.Base = "/api/v1"
+	s, err := New(conn, authv1.AuthService_ServiceDesc.ServiceName, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	r.Route("/api/v1", s.Mount)
+	return r
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string) (*httptest.ResponseRecorder, envelope) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	var env envelope
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+			t.Fatalf("decode %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec, env
+}
+
+func TestHandler(t *testing.T) {
+	conn := &fakeConn{reply: func(out proto.Message) error {
+		out.(*authv1.CheckAvailabilityResponse).EmailAvailable = true
+		return nil
+	}}
+	h := newAuth(t, conn, Options{})
+
+	_, env := do(t, h, http.MethodPost, "/api/v1/auth/availability", `{"email":"a@example.com","extra":1}`)
+	if env.Code != http.StatusOK || env.Message != "ok" {
+		t.Fatalf("envelope = %+v", env)
+	}
+	if conn.method != "/auth.v1.AuthService/CheckAvailability" {
+		t.Errorf("method = %q", conn.method)
+	}
+	if in := conn.in.(*authv1.CheckAvailabilityRequest); in.GetEmail() != "a@example.com" {
+		t.Errorf("request = %v", in)
+	}
+	// snake_case и нулевые значения в ответе
+	if got := string(env.Data); got != `{"email_available":true,"username_available":false}` {
+		t.Errorf("data = %s", got)
+	}
+	if conn.deadline {
+		t.Error("deadline set without Options.Timeout")
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	conn := &fakeConn{reply: func(proto.Message) error {
+		return status.Error(codes.AlreadyExists, "email taken")
+	}}
+	h := newAuth(t, conn, Options{})
+
+	_, env := do(t, h, http.MethodPost, "/api/v1/auth/register", `{"email":"a@example.com"}`)
+	if env.Code != http.StatusConflict || env.Message != "email taken" {
+		t.Errorf("grpc error: envelope = %+v", env)
+	}
+
+	conn.method = ""
+	_, env = do(t, h, http.MethodPost, "/api/v1/auth/register", `{"email":`)
+	if env.Code != http.StatusBadRequest || conn.method != "" {
+		t.Errorf("malformed body: envelope = %+v, invoked %q", env, conn.method)
+	}
+
+	_, env = do(t, h, http.MethodPost, "/api/v1/auth/register", `{"email":"`+strings.Repeat("a", maxBodySize)+`"}`)
+	if env.Code != http.StatusBadRequest {
+		t.Errorf("oversized body: envelope = %+v", env)
+	}
+
+	// методы без аннотации наружу не публикуются
+	if rec, _ := do(t, h, http.MethodPost, "/api/v1/auth/resolve", `{}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unannotated method: status = %d", rec.Code)
+	}
+	if rec, _ := do(t, h, http.MethodGet, "/api/v1/auth/login", ``); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("wrong verb: status = %d", rec.Code)
+	}
+}
+
+func TestHandlerMethod(t *testing.T) {
+	conn := &fakeConn{reply: func(out proto.Message) error {
+		out.(*authv1.RegisterResponse).UserId = "u1"
+		return nil
+	}}
+	h := newAuth(t, conn, Options{
+		Timeout: time.Second,
+		Methods: map[string]Method{
+			"Register": {
+				Request: func(r *http.Request, in proto.Message) error {
+					req := in.(*authv1.RegisterRequest)
+					if req.GetLocale() == "" {
+						req.Locale = r.Header.Get("Accept-Language")
+					}
+					if req.GetUsername() == "root" {
+						return status.Error(codes.InvalidArgument, "reserved username")
+					}
+					return nil
+				},
+				Response: func(_ *http.Request, out proto.Message) (any, error) {
+					return map[string]string{"id": out.(*authv1.RegisterResponse).GetUserId()}, nil
+				},
+				Status:  http.StatusCreated,
+				Message: "registered",
+			},
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(`{"username":"neo"}`))
+	req.Header.Set("Accept-Language", "uk")
+	h.ServeHTTP(rec, req)
+	var env envelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Code != http.StatusCreated || env.Message != "registered" || string(env.Data) != `{"id":"u1"}` {
+		t.Errorf("envelope = %+v data=%s", env, env.Data)
+	}
+	if got := conn.in.(*authv1.RegisterRequest).GetLocale(); got != "uk" {
+		t.Errorf("locale = %q, want default from header", got)
+	}
+	if !conn.deadline {
+		t.Error("Options.Timeout not applied")
+	}
+
+	conn.method = ""
+	_, env = do(t, h, http.MethodPost, "/api/v1/auth/register", `{"username":"root"}`)
+	if env.Code != http.StatusBadRequest || env.Message != "reserved username" || conn.method != "" {
+		t.Errorf("Request hook error: envelope = %+v, invoked %q", env, conn.method)
+	}
+}
+
+func TestNew(t *testing.T) {
+	conn := &fakeConn{}
+	cases := []struct {
+		name, service string
+		opts          Options
+		want          string
+	}{
+		{"unknown service", "auth.v1.Nope", Options{Base: "/api/v1"}, "auth.v1.Nope"},
+		{"not a service", "auth.v1.LoginRequest", Options{Base: "/api/v1"}, "is not a service"},
+		{"unknown method", "auth.v1.AuthService", Options{Base: "/api/v1", Methods: map[string]Method{"Logout": {}}}, "has no method Logout"},
+		{"path outside base", "auth.v1.AuthService", Options{Base: "/api/v2"}, "is outside /api/v2"},
+		{"base prefix of segment", "auth.v1.AuthService", Options{Base: "/api/v"}, "is outside /api/v"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(conn, tc.service, tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+}
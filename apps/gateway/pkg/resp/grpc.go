@@ -0,0 +1,47 @@
+package resp
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStatus переводит ошибку backend-сервиса в HTTP-код и сообщение для клиента.
+// Текст ошибки отдаётся только для клиентских (4xx) кодов: 5xx-сообщения
+// сервисов могут содержать внутренние подробности.
+func GRPCStatus(err error) (int, string) {
+	st := status.Convert(err)
+	code := http.StatusInternalServerError
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		code = http.StatusBadRequest
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		code = http.StatusConflict
+	case codes.ResourceExhausted:
+		code = http.StatusTooManyRequests
+	case codes.Canceled:
+		code = http.StatusRequestTimeout
+	case codes.Unimplemented:
+		return http.StatusNotImplemented, "not implemented"
+	case codes.Unavailable:
+		return http.StatusBadGateway, "bad gateway"
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout, "gateway timeout"
+	default:
+		return code, "internal error"
+	}
+	return code, st.Message()
+}
+
+// GRPC отвечает ошибкой backend-сервиса в обычном конверте BasicResponse.
+func GRPC(w http.ResponseWriter, r *http.Request, err error) {
+	code, msg := GRPCStatus(err)
+	ERROR(w, r, msg, code)
+}
@@ -3,37 +3,36 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/app"
+	"github.com/hassiimykyta/life-rpg/pkg/config"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
 )
 
 func main() {
-	a, err := app.New()
-	if err != nil {
-		log.Fatalf("app.New: %v", err)
-	}
-
-	if err := a.Start(); err != nil {
-		log.Fatalf("app.Start: %v", err)
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		if err := config.Print(os.Stdout, app.ConfigOpts...); err != nil {
+			log.Fatalf("config: %v", err)
+		}
+		return
 	}
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-
-	select {
-	case sig := <-sigCh:
-		log.Printf("shutdown signal: %v", sig)
-	case err := <-a.ErrChan():
-		if err != nil {
-			log.Printf("background error: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.Migrate(context.Background(), os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
 		}
+		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_ = a.Stop(ctx)
+	a, err := app.New()
+	if err != nil {
+		log.Fatalf("app.New: %v", err)
+	}
+	if err := a.Run(context.Background()); err != nil {
+		slog.Error("notification-svc stopped with error", logx.Err(err))
+		os.Exit(1)
+	}
+	slog.Info("notification-svc stopped")
 }
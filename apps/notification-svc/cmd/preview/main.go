@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/mailer"
+)
+
+const previewUnsubscribeURL = "http://localhost:8080/api/v1/unsubscribe?token=preview"
+
+// preview рендерит все шаблоны писем во всех локалях на фикстурах:
+//
+//	go run ./cmd/preview -out ./var/preview
+func main() {
+	out := flag.String("out", "./var/preview", "output directory for rendered emails")
+	flag.Parse()
+
+	b, err := mailer.NewTemplateBuilder()
+	if err != nil {
+		log.Fatalf("templates: %v", err)
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatalf("mkdir %s: %v", *out, err)
+	}
+
+	fixtures := mailer.Fixtures()
+	for _, name := range mailer.Templates {
+		data, ok := fixtures[name]
+		if !ok {
+			log.Fatalf("no fixture for template %q", name)
+		}
+		for _, locale := range b.Locales() {
+			opts := mailer.RenderOptions{Locale: locale}
+			if name != mailer.TemplateWelcome {
+				opts.UnsubscribeURL = previewUnsubscribeURL
+			}
+			msg, err := b.Render(name, opts, data)
+			if err != nil {
+				log.Fatalf("render %s/%s: %v", name, locale, err)
+			}
+
+			base := filepath.Join(*out, fmt.Sprintf("%s.%s", name, locale))
+			if err := os.WriteFile(base+".html", []byte(msg.HTML), 0o644); err != nil {
+				log.Fatal(err)
+			}
+			if err := os.WriteFile(base+".txt", []byte(msg.Subject+"\n\n"+msg.Text), 0o644); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("%-10s %-4s %s\n", name, locale, msg.Subject)
+		}
+	}
+}
@@ -2,84 +2,274 @@ package app
 
 import (
 	"context"
-	"log"
-	"sync"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/consumers"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/grpcapi"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/inbox"
 	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/mailer"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/repo"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/scheduler"
 	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/service"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/unsubscribe"
 	"github.com/hassiimykyta/life-rpg/pkg/config"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"github.com/hassiimykyta/life-rpg/pkg/grpcserver"
+	"github.com/hassiimykyta/life-rpg/pkg/health"
 	"github.com/hassiimykyta/life-rpg/pkg/helpers"
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
+	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+	"github.com/hassiimykyta/life-rpg/pkg/lifecycle"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
+	"github.com/hassiimykyta/life-rpg/pkg/tlsx"
+	"github.com/hassiimykyta/life-rpg/pkg/tracing"
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
 	"gopkg.in/gomail.v2"
+	"gorm.io/gorm/logger"
 )
 
 type App struct {
-	cfg         *config.Config
-	ctx         context.Context
-	cancel      context.CancelFunc
-	mailSender  *mailer.MailSender
-	mailBuilder mailer.MailBuilder
-	userReg     *consumers.UserRegistered
-	wg          sync.WaitGroup
-	errCh       chan error
+	cfg *config.Config
+	lc  *lifecycle.Runner
 }
 
+const (
+	grpcDefaultTimeout = 15 * time.Second
+	// StreamInbox переоткрывается браузером (EventSource) после обрыва
+	grpcStreamTimeout = time.Hour
+)
+
 func New() (*App, error) {
-	cfg, err := config.Load(config.WithSMTP())
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	logx.Setup(logx.Options{Service: "notification-svc", Env: cfg.App.Env, Level: cfg.App.LogLevel, Format: cfg.App.LogFormat})
+
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options{
+		Service:     "notification-svc",
+		Env:         cfg.App.Env,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	brokers := helpers.Csv(helpers.GetEnv("KAFKA_BROKERS", "kafka:9092"))
 	groupID := helpers.GetEnv("KAFKA_GROUP_ID", "notification-svc")
+	maxAttempts := helpers.MustInt(helpers.GetEnv("NOTIFY_MAX_ATTEMPTS", "5"), 5)
+	retryInterval := helpers.MustDur(helpers.GetEnv("NOTIFY_RETRY_INTERVAL", "30s"), 30*time.Second)
+	unsubSecret := helpers.GetEnv("UNSUBSCRIBE_SECRET", "dev_unsubscribe_secret_change_me")
+	unsubBaseURL := helpers.GetEnv("UNSUBSCRIBE_BASE_URL", "http://localhost:8080/api/v1/unsubscribe")
+	if unsubSecret == "" {
+		return nil, errors.New("UNSUBSCRIBE_SECRET must not be empty")
+	}
+	retryBatch := helpers.MustInt(helpers.GetEnv("NOTIFY_RETRY_BATCH", "50"), 50)
+	digestInterval := helpers.MustDur(helpers.GetEnv("NOTIFY_DIGEST_INTERVAL", "5m"), 5*time.Minute)
+	digestBatch := helpers.MustInt(helpers.GetEnv("NOTIFY_DIGEST_BATCH", "100"), 100)
+
+	conn, err := db.Open(db.Options{
+		DSN:           cfg.DB.DSN,
+		ReplicaDSNs:   cfg.DB.ReplicaDSNs,
+		MaxOpen:       cfg.DB.MaxOpen,
+		MaxIdle:       cfg.DB.MaxIdle,
+		MaxIdleTime:   cfg.DB.MaxIdleTime,
+		MaxLifetime:   cfg.DB.MaxLifetime,
+		QueryTimeout:  cfg.DB.QueryTimeout,
+		SlowThreshold: cfg.DB.SlowThreshold,
+		LogLevel:      logger.Warn,
+		SingularTable: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DB.MigrateOnStart {
+		if err := migrateUp(context.Background(), conn); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := metrics.RegisterDBStats("notification", conn.SQL); err != nil {
+		return nil, err
+	}
+
+	notifications := repo.NewNotificationRepo(conn.Gorm)
+	inboxItems := repo.NewInboxRepo(conn.Gorm)
+	prefs := repo.NewPreferenceRepo(conn.Gorm)
+	digests := repo.NewDigestRepo(conn.Gorm)
+	suppressions := repo.NewSuppressionRepo(conn.Gorm)
+	txm := db.NewTxManager(conn.Gorm)
+	hub := inbox.NewHub()
+	ids := ulid.NewULIDGenerator()
+	// короткий батч: элемент должен дойти до открытого стрима почти сразу
+	fanoutProducer := kafka.NewProducer(kafka.ProducerConfig{
+		Brokers:      brokers,
+		Topic:        inbox.Topic,
+		BatchTimeout: 10 * time.Millisecond,
+	})
 
 	d := gomail.NewDialer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password)
-	sender := mailer.New(d, "life-rpg@noreply.com")
-	builder := mailer.NewHTMLBuilder()
-	svc := service.NewNotificationService(builder, sender)
+	pool := mailer.NewPool(d, mailer.PoolConfig{
+		MaxConns:    cfg.SMTP.PoolSize,
+		IdleTimeout: cfg.SMTP.IdleTimeout,
+		MaxPerConn:  cfg.SMTP.MaxPerConn,
+		RateLimit:   rate.Limit(cfg.SMTP.RateLimit),
+		RateBurst:   cfg.SMTP.RateBurst,
+	})
+	sender := mailer.New(pool, "life-rpg@noreply.com")
+	builder, err := mailer.NewTemplateBuilder()
+	if err != nil {
+		return nil, err
+	}
+	inboxSvc := service.NewInboxService(inboxItems, inbox.NewFanout(fanoutProducer, hub), ids)
+	svc := service.NewNotificationService(service.Deps{
+		Builder:       builder,
+		Sender:        sender,
+		Notifications: notifications,
+		Prefs:         prefs,
+		Digests:       digests,
+		Inbox:         inboxSvc,
+		Suppressions:  suppressions,
+		Unsubscribe:   unsubscribe.NewSigner(unsubSecret, unsubBaseURL),
+		IDs:           ids,
+		Tx:            txm,
+		MaxAttempts:   maxAttempts,
+	})
 
 	userReg := consumers.NewUserRegistered(brokers, groupID, svc)
+	inboxReq := consumers.NewInboxRequested(brokers, groupID, svc)
+	// своя группа на инстанс: каждая реплика получает все новые элементы
+	fanoutGroup, err := ids.New()
+	if err != nil {
+		return nil, err
+	}
+	inboxFanout := consumers.NewInboxFanout(brokers, groupID+"-fanout-"+fanoutGroup, hub)
+	retry := scheduler.NewRetry(svc, retryInterval, retryBatch)
+	digest := scheduler.NewDigest(svc, digestInterval, digestBatch)
 
-	ctx, cancel := context.WithCancel(context.Background())
-
-	return &App{
-		cfg:         cfg,
-		ctx:         ctx,
-		cancel:      cancel,
-		mailSender:  sender,
-		mailBuilder: builder,
-		userReg:     userReg,
-		errCh:       make(chan error, 1),
-	}, nil
-}
+	var (
+		certs     *tlsx.Reloader
+		serverTLS *tls.Config
+	)
+	if cfg.TLS.Enabled {
+		certs, err = tlsx.NewReloader(tlsx.Options{
+			CertFile:       cfg.TLS.CertFile,
+			KeyFile:        cfg.TLS.KeyFile,
+			CAFile:         cfg.TLS.CAFile,
+			ReloadInterval: cfg.TLS.ReloadInterval,
+		})
+		if err != nil {
+			return nil, err
+		}
+		serverTLS = certs.ServerConfig(cfg.TLS.AllowedPeers)
+	}
 
-func (a *App) Start() error {
-	log.Printf("notification-svc starting (env=%s)", a.cfg.App.Env)
+	jwtMgr := jwt.NewManager(cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL)
+	gs, err := grpcserver.New(grpcserver.Options{
+		Addr:           ":" + cfg.App.Port,
+		TLS:            serverTLS,
+		Reflection:     cfg.App.Env == "dev",
+		DefaultTimeout: grpcDefaultTimeout,
+		StreamTimeout:  grpcStreamTimeout,
+		Authenticate:   grpcserver.JWTAuth(jwtMgr),
+		// отписка по подписанной ссылке приходит без пользователя
+		PublicMethods: []string{notificationv1.NotificationService_Unsubscribe_FullMethodName},
+		// вебхук провайдера gateway проверяет по подписи и передаёт с сервисным токеном
+		ServiceMethods:     []string{notificationv1.NotificationService_ReportDeliveryEvent_FullMethodName},
+		UnaryInterceptors:  []grpc.UnaryServerInterceptor{grpcapi.OwnerUnaryInterceptor()},
+		StreamInterceptors: []grpc.StreamServerInterceptor{grpcapi.OwnerStreamInterceptor()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	notificationv1.RegisterNotificationServiceServer(gs.GRPC, grpcapi.New(notifications, inboxItems, prefs, txm, hub, svc))
 
-	a.wg.Add(1)
-	go func() {
-		defer a.wg.Done()
-		if err := a.userReg.Start(a.ctx); err != nil && err != context.Canceled {
-			a.errCh <- err
-		}
-	}()
+	checker := health.New(health.Options{})
+	checker.Add("db", conn.HealthPing)
+	checker.Add("kafka", func(ctx context.Context) error { return kafka.Ping(ctx, brokers) })
+	checker.RegisterGRPC(gs.GRPC)
 
-	log.Printf("notification-svc started (brokers=%v, group=%s)",
-		helpers.Csv(helpers.GetEnv("KAFKA_BROKERS", "kafka:9092")),
-		helpers.GetEnv("KAFKA_GROUP_ID", "notification-svc"),
+	admin, err := metrics.NewAdminServer(":"+cfg.App.AdminPort, checker.Mount)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := config.NewWatcher(cfg, ConfigOpts...)
+	watcher.OnReload(func(_, cur *config.Config) {
+		logx.SetLevel(cur.App.LogLevel)
+		jwtMgr.SetTTL(cur.JWT.AccessTTL, cur.JWT.RefreshTTL)
+	})
+
+	lc := lifecycle.New(lifecycle.Options{
+		ShutdownTimeout: cfg.App.ShutdownTimeout,
+		DrainDelay:      cfg.App.DrainDelay,
+		Drain:           checker.Shutdown,
+	})
+	lc.Append(
+		lifecycle.Hook{Name: "tracing", Stop: shutdownTracing},
+		lifecycle.Hook{Name: "db", Stop: func(context.Context) error { return conn.SQL.Close() }},
+		lifecycle.Hook{Name: "mailer", Stop: func(context.Context) error { return sender.Close() }},
+		lifecycle.Hook{Name: "inbox fan-out producer", Stop: func(context.Context) error { return fanoutProducer.Close() }},
 	)
-	return nil
-}
+	if certs != nil {
+		lc.Go("tls reloader", func(ctx context.Context) error { certs.Run(ctx); return nil })
+	}
+	lc.Go("config watcher", func(ctx context.Context) error { watcher.Run(ctx, configReloadInterval); return nil })
+	lc.Append(lifecycle.Hook{Name: "health", Start: func(ctx context.Context) error { checker.Start(ctx); return nil }})
 
-func (a *App) Stop(_ context.Context) error {
-	a.cancel()
-	a.wg.Wait()
+	// консьюмер закрывается после того, как его цикл чтения вернулся
+	lc.Append(lifecycle.Hook{Name: "user_registered consumer", Stop: func(context.Context) error { return userReg.Close() }})
+	lc.Go("user_registered", userReg.Start)
+	lc.Append(lifecycle.Hook{Name: "inbox_requested consumer", Stop: func(context.Context) error { return inboxReq.Close() }})
+	lc.Go("inbox_requested", inboxReq.Start)
+	lc.Append(lifecycle.Hook{Name: "inbox fan-out consumer", Stop: func(context.Context) error { return inboxFanout.Close() }})
+	lc.Go("inbox fan-out", inboxFanout.Start)
+	lc.Go("retry scheduler", retry.Start)
+	lc.Go("digest scheduler", digest.Start)
 
-	_ = a.userReg.Close()
+	lc.Append(
+		lifecycle.Hook{
+			Name:  "admin",
+			Start: func(context.Context) error { admin.Start(); return nil },
+			Stop:  admin.Shutdown,
+		},
+		lifecycle.Hook{
+			Name: "grpc",
+			Start: func(context.Context) error {
+				gs.Start()
+				slog.Info("notification-svc started",
+					"grpc_port", cfg.App.Port,
+					"admin_port", cfg.App.AdminPort,
+					"env", cfg.App.Env,
+					"brokers", brokers,
+					"group", helpers.GetEnv("KAFKA_GROUP_ID", "notification-svc"),
+				)
+				return nil
+			},
+			Stop: gs.Shutdown,
+		},
+		// стримы inbox держат GracefulStop, поэтому hub закрывается раньше gRPC
+		lifecycle.Hook{Name: "inbox hub", Stop: func(context.Context) error { hub.Close(); return nil }},
+	)
 
-	close(a.errCh)
-	log.Println("notification-svc stopped")
-	return nil
+	// упавший Serve (не Shutdown) останавливает сервис, а не оставляет его без порта
+	lc.Watch("admin", admin.Err())
+	lc.Watch("grpc", gs.Err())
+
+	return &App{cfg: cfg, lc: lc}, nil
 }
 
-func (a *App) ErrChan() <-chan error { return a.errCh }
+// Run работает до SIGINT/SIGTERM или падения консьюмера/планировщика.
+func (a *App) Run(ctx context.Context) error {
+	return a.lc.Run(ctx)
+}
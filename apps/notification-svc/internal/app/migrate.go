@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/migrations"
+	"github.com/hassiimykyta/life-rpg/pkg/config"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"gorm.io/gorm/logger"
+)
+
+// Migrate выполняет подкоманду `migrate up | down [n] | status` и завершается,
+// не поднимая остальной сервис.
+func Migrate(ctx context.Context, args []string) error {
+	cfg, err := config.Load(config.WithDB())
+	if err != nil {
+		return err
+	}
+	logx.Setup(logx.Options{Service: "notification-svc", Env: cfg.App.Env, Level: cfg.App.LogLevel, Format: cfg.App.LogFormat})
+
+	conn, err := db.Open(db.Options{
+		DSN:           cfg.DB.DSN,
+		LogLevel:      logger.Warn,
+		SingularTable: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.SQL.Close()
+
+	m, err := db.NewMigrator(conn.SQL, migrations.FS)
+	if err != nil {
+		return err
+	}
+	return db.RunMigrateCommand(ctx, m, args, os.Stdout)
+}
+
+func migrateUp(ctx context.Context, conn *db.Conn) error {
+	m, err := db.NewMigrator(conn.SQL, migrations.FS)
+	if err != nil {
+		return err
+	}
+	n, err := m.Up(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "migrations applied", "count", n)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/inbox"
+	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	notificationeventsv1 "github.com/hassiimykyta/life-rpg/services/events/notification/v1"
+)
+
+// InboxFanout отдаёт новые элементы инбокса в стримы этой реплики.
+// groupID должен быть уникальным для инстанса: каждая реплика читает все
+// сообщения, и только новые — пропущенное клиент добирает через ListInbox.
+type InboxFanout struct {
+	c   *kafka.Consumer
+	hub *inbox.Hub
+}
+
+func NewInboxFanout(brokers []string, groupID string, h *inbox.Hub) *InboxFanout {
+	return &InboxFanout{
+		c: kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers:     brokers,
+			Topic:       inbox.Topic,
+			GroupID:     groupID,
+			StartLatest: true,
+		}),
+		hub: h,
+	}
+}
+
+func (u *InboxFanout) Start(ctx context.Context) error {
+	return u.c.Start(ctx, func(ctx context.Context, m kafka.Message) error {
+		var evt notificationeventsv1.InboxItemCreated
+		if err := json.Unmarshal(m.Value, &evt); err != nil {
+			slog.WarnContext(ctx, "bad payload", "topic", m.Topic, logx.Err(err))
+			return nil
+		}
+		u.hub.Publish(inbox.FromEvent(&evt))
+		return nil
+	})
+}
+
+func (u *InboxFanout) Close() error { return u.c.Close() }
@@ -0,0 +1,47 @@
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	notificationeventsv1 "github.com/hassiimykyta/life-rpg/services/events/notification/v1"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, userID, kind, title, body string, data map[string]string) error
+}
+
+type InboxRequested struct {
+	c       *kafka.Consumer
+	handler Notifier
+}
+
+func NewInboxRequested(brokers []string, groupID string, h Notifier) *InboxRequested {
+	return &InboxRequested{
+		c: kafka.NewConsumer(kafka.ConsumerConfig{
+			Brokers: brokers,
+			Topic:   "notification.inbox",
+			GroupID: groupID,
+		}),
+		handler: h,
+	}
+}
+
+func (u *InboxRequested) Start(ctx context.Context) error {
+	return u.c.Start(ctx, func(ctx context.Context, m kafka.Message) error {
+		var evt notificationeventsv1.InboxNotificationRequested
+		if err := json.Unmarshal(m.Value, &evt); err != nil {
+			slog.WarnContext(ctx, "bad payload", "topic", m.Topic, logx.Err(err))
+			return nil
+		}
+		if err := u.handler.Notify(ctx, evt.UserId, evt.Kind, evt.Title, evt.Body, evt.Data); err != nil {
+			slog.ErrorContext(ctx, "notify failed", "topic", m.Topic, "user_id", evt.UserId, logx.Err(err))
+		}
+		return nil
+	})
+}
+
+func (u *InboxRequested) Close() error { return u.c.Close() }
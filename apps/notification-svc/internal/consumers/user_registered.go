@@ -3,14 +3,15 @@ package consumers
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 
 	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
 	usereventsv1 "github.com/hassiimykyta/life-rpg/services/events/user/v1"
 )
 
 type WelcomeSender interface {
-	SendWelcome(to, username string) error
+	SendWelcome(ctx context.Context, userID, to, username, locale string) error
 }
 
 type UserRegistered struct {
@@ -30,14 +31,14 @@ func NewUserRegistered(brokers []string, groupID string, h WelcomeSender) *UserR
 }
 
 func (u *UserRegistered) Start(ctx context.Context) error {
-	return u.c.Start(ctx, func(m kafka.Message) error {
+	return u.c.Start(ctx, func(ctx context.Context, m kafka.Message) error {
 		var evt usereventsv1.UserRegistered
 		if err := json.Unmarshal(m.Value, &evt); err != nil {
-			log.Printf("[user.registered] bad payload: %v", err)
+			slog.WarnContext(ctx, "bad payload", "topic", m.Topic, logx.Err(err))
 			return nil
 		}
-		if err := u.handler.SendWelcome(evt.Email, evt.Username); err != nil {
-			log.Printf("[user.registered] send welcome failed: %v", err)
+		if err := u.handler.SendWelcome(ctx, evt.UserId, evt.Email, evt.Username, evt.Locale); err != nil {
+			slog.ErrorContext(ctx, "send welcome failed", "topic", m.Topic, "user_id", evt.UserId, logx.Err(err))
 		}
 		return nil
 	})
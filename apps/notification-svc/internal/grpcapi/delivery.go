@@ -0,0 +1,50 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/service"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/unsubscribe"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type DeliveryHandler interface {
+	Unsubscribe(ctx context.Context, token string, dryRun bool) (unsubscribe.Claims, error)
+	HandleDeliveryEvent(ctx context.Context, typ, email, messageID string, permanent bool, reason string) error
+}
+
+func (s *Server) Unsubscribe(ctx context.Context, in *notificationv1.UnsubscribeRequest) (*notificationv1.UnsubscribeResponse, error) {
+	if in.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token required")
+	}
+
+	c, err := s.delivery.Unsubscribe(ctx, in.GetToken(), in.GetDryRun())
+	if errors.Is(err, unsubscribe.ErrInvalidToken) {
+		return nil, status.Error(codes.InvalidArgument, "invalid token")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "unsubscribe failed")
+	}
+
+	return &notificationv1.UnsubscribeResponse{Email: c.Email, Kind: c.Kind}, nil
+}
+
+func (s *Server) ReportDeliveryEvent(ctx context.Context, in *notificationv1.ReportDeliveryEventRequest) (*notificationv1.ReportDeliveryEventResponse, error) {
+	switch in.GetType() {
+	case service.DeliveryEventBounce, service.DeliveryEventComplaint:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "type must be bounce or complaint")
+	}
+	if in.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email required")
+	}
+
+	err := s.delivery.HandleDeliveryEvent(ctx, in.GetType(), in.GetEmail(), in.GetProviderMessageId(), in.GetPermanent(), in.GetReason())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "delivery event failed")
+	}
+	return &notificationv1.ReportDeliveryEventResponse{}, nil
+}
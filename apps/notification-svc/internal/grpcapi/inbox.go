@@ -0,0 +1,104 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ListInbox(ctx context.Context, in *notificationv1.ListInboxRequest) (*notificationv1.ListInboxResponse, error) {
+	if in.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id required")
+	}
+
+	size := pageSize(in.GetPageSize())
+
+	items, err := s.inbox.ListByUser(ctx, in.GetUserId(), in.GetUnreadOnly(), in.GetPageToken(), size+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "lookup failed")
+	}
+	unread, err := s.inbox.CountUnread(ctx, in.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "count failed")
+	}
+
+	out := &notificationv1.ListInboxResponse{UnreadCount: unread}
+	if len(items) > size {
+		items = items[:size]
+		out.NextPageToken = items[size-1].ID
+	}
+	for _, it := range items {
+		out.Items = append(out.Items, inboxToProto(it))
+	}
+	return out, nil
+}
+
+func (s *Server) MarkInboxRead(ctx context.Context, in *notificationv1.MarkInboxReadRequest) (*notificationv1.MarkInboxReadResponse, error) {
+	if in.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id required")
+	}
+	if !in.GetAll() && len(in.GetIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ids or all required")
+	}
+
+	var ids []string
+	if !in.GetAll() {
+		ids = in.GetIds()
+	}
+
+	n, err := s.inbox.MarkRead(ctx, in.GetUserId(), ids, time.Now())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "update failed")
+	}
+	unread, err := s.inbox.CountUnread(ctx, in.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "count failed")
+	}
+
+	return &notificationv1.MarkInboxReadResponse{Updated: n, UnreadCount: unread}, nil
+}
+
+func (s *Server) StreamInbox(in *notificationv1.StreamInboxRequest, stream grpc.ServerStreamingServer[notificationv1.InboxItem]) error {
+	if in.GetUserId() == "" {
+		return status.Error(codes.InvalidArgument, "user id required")
+	}
+
+	ch, cancel := s.hub.Subscribe(in.GetUserId())
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case it, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(inboxToProto(it)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func inboxToProto(it models.InboxItem) *notificationv1.InboxItem {
+	p := &notificationv1.InboxItem{
+		Id:        it.ID,
+		UserId:    it.UserID,
+		Kind:      it.Kind,
+		Title:     it.Title,
+		Body:      it.Body,
+		Data:      it.Data,
+		Read:      it.ReadAt != nil,
+		CreatedAt: it.CreatedAt.Unix(),
+	}
+	if it.ReadAt != nil {
+		p.ReadAt = it.ReadAt.Unix()
+	}
+	return p
+}
@@ -0,0 +1,54 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/hassiimykyta/life-rpg/pkg/grpcserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type userScoped interface {
+	GetUserId() string
+}
+
+// OwnerUnaryInterceptor не даёт читать/менять чужие данные: user_id запроса
+// должен совпадать с subject токена. Публичные методы и вызовы с сервисным
+// токеном (без subject) пропускаются.
+func OwnerUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkOwner(ctx, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func OwnerStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &ownerStream{ServerStream: ss})
+	}
+}
+
+type ownerStream struct {
+	grpc.ServerStream
+}
+
+func (s *ownerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkOwner(s.Context(), m)
+}
+
+func checkOwner(ctx context.Context, req any) error {
+	sub := grpcserver.Subject(ctx)
+	if sub == "" {
+		return nil
+	}
+	if r, ok := req.(userScoped); ok && r.GetUserId() != "" && r.GetUserId() != sub {
+		return status.Error(codes.PermissionDenied, "user mismatch")
+	}
+	return nil
+}
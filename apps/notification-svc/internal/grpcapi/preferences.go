@@ -0,0 +1,166 @@
+package grpcapi
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/service"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) GetPreferences(ctx context.Context, in *notificationv1.GetPreferencesRequest) (*notificationv1.GetPreferencesResponse, error) {
+	if in.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id required")
+	}
+
+	out, err := s.loadPreferences(ctx, in.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "lookup failed")
+	}
+	return &notificationv1.GetPreferencesResponse{Preferences: out}, nil
+}
+
+func (s *Server) UpdatePreferences(ctx context.Context, in *notificationv1.UpdatePreferencesRequest) (*notificationv1.UpdatePreferencesResponse, error) {
+	userID := in.GetUserId()
+	p := in.GetPreferences()
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id required")
+	}
+	if p == nil {
+		return nil, status.Error(codes.InvalidArgument, "preferences required")
+	}
+	if err := validatePreferences(p); err != nil {
+		return nil, err
+	}
+
+	var out *notificationv1.Preferences
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		settings, ok, err := s.prefs.GetSettings(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			settings = models.UserSettings{UserID: userID, Locale: "en"}
+		}
+		settings.Timezone = p.GetTimezone()
+		if settings.Timezone == "" {
+			settings.Timezone = "UTC"
+		}
+		settings.QuietStart = canonClock(p.GetQuietStart())
+		settings.QuietEnd = canonClock(p.GetQuietEnd())
+		settings.DigestMode = p.GetDigestMode()
+		if err := s.prefs.SaveSettings(ctx, &settings); err != nil {
+			return err
+		}
+
+		prefs := make([]models.Preference, 0, len(p.GetChannels()))
+		for _, c := range p.GetChannels() {
+			prefs = append(prefs, models.Preference{
+				UserID:  userID,
+				Kind:    c.GetKind(),
+				Channel: c.GetChannel(),
+				Enabled: c.GetEnabled(),
+			})
+		}
+		if err := s.prefs.SetPreferences(ctx, prefs); err != nil {
+			return err
+		}
+
+		out, err = s.loadPreferences(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "update failed")
+	}
+	return &notificationv1.UpdatePreferencesResponse{Preferences: out}, nil
+}
+
+// canonClock приводит уже проверенное время к виду "HH:MM": "7:05" → "07:05".
+func canonClock(s string) string {
+	if s == "" {
+		return ""
+	}
+	m, _ := service.ParseClock(s)
+	return service.FormatClock(m)
+}
+
+func validatePreferences(p *notificationv1.Preferences) error {
+	if tz := p.GetTimezone(); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return status.Error(codes.InvalidArgument, "unknown timezone")
+		}
+	}
+
+	if (p.GetQuietStart() == "") != (p.GetQuietEnd() == "") {
+		return status.Error(codes.InvalidArgument, "quiet_start and quiet_end must be set together")
+	}
+	if p.GetQuietStart() != "" {
+		if _, err := service.ParseClock(p.GetQuietStart()); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if _, err := service.ParseClock(p.GetQuietEnd()); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	switch p.GetDigestMode() {
+	case models.DigestOff, models.DigestDaily, models.DigestWeekly:
+	default:
+		return status.Error(codes.InvalidArgument, "digest_mode must be off, daily or weekly")
+	}
+
+	for _, c := range p.GetChannels() {
+		if !slices.Contains(models.Kinds, c.GetKind()) {
+			return status.Errorf(codes.InvalidArgument, "unknown kind %q", c.GetKind())
+		}
+		if !slices.Contains(models.Channels, c.GetChannel()) {
+			return status.Errorf(codes.InvalidArgument, "unknown channel %q", c.GetChannel())
+		}
+	}
+	return nil
+}
+
+// loadPreferences отдаёт полную матрицу тип × канал: без явной настройки канал включён.
+func (s *Server) loadPreferences(ctx context.Context, userID string) (*notificationv1.Preferences, error) {
+	settings, ok, err := s.prefs.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		settings = models.UserSettings{Timezone: "UTC", DigestMode: models.DigestDaily}
+	}
+
+	stored, err := s.prefs.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := make(map[[2]string]bool, len(stored))
+	for _, p := range stored {
+		enabled[[2]string{p.Kind, p.Channel}] = p.Enabled
+	}
+
+	out := &notificationv1.Preferences{
+		Timezone:   settings.Timezone,
+		QuietStart: settings.QuietStart,
+		QuietEnd:   settings.QuietEnd,
+		DigestMode: settings.DigestMode,
+	}
+	for _, kind := range models.Kinds {
+		for _, ch := range models.Channels {
+			on, ok := enabled[[2]string{kind, ch}]
+			if !ok {
+				on = true
+			}
+			out.Channels = append(out.Channels, &notificationv1.ChannelPreference{
+				Kind:    kind,
+				Channel: ch,
+				Enabled: on,
+			})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/inbox"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/repo"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	notificationv1 "github.com/hassiimykyta/life-rpg/services/notification/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type Server struct {
+	notificationv1.UnimplementedNotificationServiceServer
+	repo     *repo.NotificationRepo
+	inbox    *repo.InboxRepo
+	prefs    *repo.PreferenceRepo
+	tx       *db.TxManager
+	hub      *inbox.Hub
+	delivery DeliveryHandler
+}
+
+func New(r *repo.NotificationRepo, ir *repo.InboxRepo, pr *repo.PreferenceRepo, tx *db.TxManager, h *inbox.Hub, d DeliveryHandler) *Server {
+	return &Server{repo: r, inbox: ir, prefs: pr, tx: tx, hub: h, delivery: d}
+}
+
+func (s *Server) ListUserNotifications(ctx context.Context, in *notificationv1.ListUserNotificationsRequest) (*notificationv1.ListUserNotificationsResponse, error) {
+	if in.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id required")
+	}
+
+	size := pageSize(in.GetPageSize())
+
+	items, err := s.repo.ListByUser(ctx, in.GetUserId(), in.GetPageToken(), size+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "lookup failed")
+	}
+
+	out := &notificationv1.ListUserNotificationsResponse{}
+	if len(items) > size {
+		items = items[:size]
+		out.NextPageToken = items[size-1].ID
+	}
+	for _, n := range items {
+		out.Notifications = append(out.Notifications, toProto(n))
+	}
+	return out, nil
+}
+
+func pageSize(n int32) int {
+	size := int(n)
+	if size <= 0 {
+		return defaultPageSize
+	}
+	if size > maxPageSize {
+		return maxPageSize
+	}
+	return size
+}
+
+func toProto(n models.Notification) *notificationv1.Notification {
+	p := &notificationv1.Notification{
+		Id:                n.ID,
+		UserId:            n.UserID,
+		Channel:           n.Channel,
+		Template:          n.Template,
+		Recipient:         n.Recipient,
+		Subject:           n.Subject,
+		Status:            n.Status,
+		Attempts:          int32(n.Attempts),
+		ProviderMessageId: n.ProviderMessageID,
+		Error:             n.Error,
+		CreatedAt:         n.CreatedAt.Unix(),
+	}
+	if n.SentAt != nil {
+		p.SentAt = n.SentAt.Unix()
+	}
+	return p
+}
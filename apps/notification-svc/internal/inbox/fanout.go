@@ -0,0 +1,69 @@
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	notificationeventsv1 "github.com/hassiimykyta/life-rpg/services/events/notification/v1"
+)
+
+// Topic — новые элементы инбокса; его читают все реплики, каждая своей группой.
+const Topic = "notification.inbox.created"
+
+// Fanout доносит новый элемент до стримов на всех репликах: стрим пользователя
+// может быть открыт не на том инстансе, который записал элемент.
+type Fanout struct {
+	p   *kafka.Producer
+	hub *Hub
+}
+
+// NewFanout без продюсера раздаёт элементы только локальному hub (одна реплика).
+func NewFanout(p *kafka.Producer, h *Hub) *Fanout {
+	return &Fanout{p: p, hub: h}
+}
+
+// Publish не возвращает ошибку: элемент уже сохранён, и клиент получит его через
+// ListInbox. Если Kafka недоступна, элемент отдаётся хотя бы стримам этой реплики.
+func (f *Fanout) Publish(ctx context.Context, it models.InboxItem) {
+	if f.p == nil {
+		f.hub.Publish(it)
+		return
+	}
+	raw, err := json.Marshal(Event(it))
+	if err == nil {
+		err = f.p.Send(ctx, []byte(it.UserID), raw)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "inbox fan-out failed, publishing locally", "user_id", it.UserID, logx.Err(err))
+		f.hub.Publish(it)
+	}
+}
+
+func Event(it models.InboxItem) *notificationeventsv1.InboxItemCreated {
+	return &notificationeventsv1.InboxItemCreated{
+		Id:        it.ID,
+		UserId:    it.UserID,
+		Kind:      it.Kind,
+		Title:     it.Title,
+		Body:      it.Body,
+		Data:      it.Data,
+		CreatedAt: it.CreatedAt.UnixMilli(),
+	}
+}
+
+func FromEvent(e *notificationeventsv1.InboxItemCreated) models.InboxItem {
+	return models.InboxItem{
+		ID:        e.Id,
+		UserID:    e.UserId,
+		Kind:      e.Kind,
+		Title:     e.Title,
+		Body:      e.Body,
+		Data:      e.Data,
+		CreatedAt: time.UnixMilli(e.CreatedAt),
+	}
+}
@@ -0,0 +1,60 @@
+package inbox
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/kafka"
+)
+
+func TestEventRoundTrip(t *testing.T) {
+	it := models.InboxItem{
+		ID:        "01HX",
+		UserID:    "u1",
+		Kind:      "quest",
+		Title:     "t",
+		Body:      "b",
+		Data:      map[string]string{"quest_id": "42"},
+		CreatedAt: time.UnixMilli(1_700_000_000_123),
+	}
+	got := FromEvent(Event(it))
+	if !got.CreatedAt.Equal(it.CreatedAt) {
+		t.Fatalf("created_at: got %v, want %v", got.CreatedAt, it.CreatedAt)
+	}
+	got.CreatedAt = it.CreatedAt
+	if !reflect.DeepEqual(got, it) {
+		t.Fatalf("got %+v, want %+v", got, it)
+	}
+}
+
+func TestFanoutWithoutProducerPublishesLocally(t *testing.T) {
+	h := NewHub()
+	ch, cancel := h.Subscribe("u1")
+	defer cancel()
+
+	NewFanout(nil, h).Publish(context.Background(), models.InboxItem{ID: "1", UserID: "u1"})
+
+	if it, _ := recv(t, ch); it.ID != "1" {
+		t.Fatalf("got %+v", it)
+	}
+}
+
+func TestFanoutFallsBackWhenKafkaIsDown(t *testing.T) {
+	h := NewHub()
+	ch, cancel := h.Subscribe("u1")
+	defer cancel()
+
+	p := kafka.NewProducer(kafka.ProducerConfig{Brokers: []string{"127.0.0.1:1"}, Topic: Topic, BatchTimeout: time.Millisecond})
+	defer p.Close()
+
+	ctx, stop := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer stop()
+	NewFanout(p, h).Publish(ctx, models.InboxItem{ID: "1", UserID: "u1"})
+
+	if it, _ := recv(t, ch); it.ID != "1" {
+		t.Fatalf("got %+v", it)
+	}
+}
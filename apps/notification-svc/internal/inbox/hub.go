@@ -0,0 +1,80 @@
+package inbox
+
+import (
+	"sync"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+)
+
+const subscriberBuffer = 16
+
+// Hub раздаёт новые элементы инбокса подписчикам (стримам StreamInbox) этого инстанса.
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[string]map[chan models.InboxItem]struct{}
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[string]map[chan models.InboxItem]struct{})}
+}
+
+// Subscribe после Close отдаёт уже закрытый канал: стрим сразу завершается.
+func (h *Hub) Subscribe(userID string) (<-chan models.InboxItem, func()) {
+	ch := make(chan models.InboxItem, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[chan models.InboxItem]struct{})
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[userID][ch]; !ok {
+			return
+		}
+		delete(h.subs[userID], ch)
+		if len(h.subs[userID]) == 0 {
+			delete(h.subs, userID)
+		}
+		close(ch)
+	}
+	return ch, cancel
+}
+
+// Publish не блокируется: медленный подписчик пропускает элемент и догонит через ListInbox.
+func (h *Hub) Publish(it models.InboxItem) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subs[it.UserID] {
+		select {
+		case ch <- it:
+		default:
+		}
+	}
+}
+
+// Close закрывает все подписки, чтобы открытые стримы завершились и не держали GracefulStop.
+// Повторный вызов ничего не делает.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for userID, subs := range h.subs {
+		for ch := range subs {
+			close(ch)
+		}
+		delete(h.subs, userID)
+	}
+}
@@ -0,0 +1,85 @@
+package inbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+)
+
+func recv(t *testing.T, ch <-chan models.InboxItem) (models.InboxItem, bool) {
+	t.Helper()
+	select {
+	case it, ok := <-ch:
+		return it, ok
+	case <-time.After(time.Second):
+		t.Fatal("no item within 1s")
+		return models.InboxItem{}, false
+	}
+}
+
+func TestHubDeliversOnlyToOwner(t *testing.T) {
+	h := NewHub()
+	mine, cancelMine := h.Subscribe("u1")
+	defer cancelMine()
+	other, cancelOther := h.Subscribe("u2")
+	defer cancelOther()
+
+	h.Publish(models.InboxItem{ID: "1", UserID: "u1"})
+
+	if it, _ := recv(t, mine); it.ID != "1" {
+		t.Fatalf("got %+v", it)
+	}
+	select {
+	case it := <-other:
+		t.Fatalf("foreign subscriber got %+v", it)
+	default:
+	}
+}
+
+func TestHubSlowSubscriberDoesNotBlock(t *testing.T) {
+	h := NewHub()
+	ch, cancel := h.Subscribe("u1")
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer*2; i++ {
+			h.Publish(models.InboxItem{UserID: "u1"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full subscriber")
+	}
+	if len(ch) != subscriberBuffer {
+		t.Fatalf("buffered %d items, want %d", len(ch), subscriberBuffer)
+	}
+}
+
+func TestHubCancelAndClose(t *testing.T) {
+	h := NewHub()
+	a, cancelA := h.Subscribe("u1")
+	b, _ := h.Subscribe("u1")
+
+	cancelA()
+	cancelA() // повторная отмена не паникует
+	if _, ok := recv(t, a); ok {
+		t.Fatal("cancelled channel is still open")
+	}
+
+	h.Close()
+	h.Close()
+	if _, ok := recv(t, b); ok {
+		t.Fatal("Close left a subscription open")
+	}
+
+	late, cancelLate := h.Subscribe("u1")
+	cancelLate()
+	if _, ok := recv(t, late); ok {
+		t.Fatal("Subscribe after Close returned an open channel")
+	}
+	h.Publish(models.InboxItem{UserID: "u1"}) // после Close не паникует
+}
@@ -1,6 +1,55 @@
 package mailer
 
+type Template string
+
+const (
+	TemplateWelcome Template = "welcome"
+	TemplateEvent   Template = "event"
+	TemplateDigest  Template = "digest"
+)
+
+// Templates перечисляет все шаблоны, которые парсятся при старте и рендерятся в preview.
+var Templates = []Template{
+	TemplateWelcome,
+	TemplateEvent,
+	TemplateDigest,
+}
+
+type Message struct {
+	Subject        string
+	HTML           string
+	Text           string
+	UnsubscribeURL string
+}
+
+type RenderOptions struct {
+	Locale string
+	// UnsubscribeURL попадает в футер и в заголовок List-Unsubscribe; пусто — без ссылки.
+	UnsubscribeURL string
+}
+
+type WelcomeData struct {
+	Username string
+}
+
+type EventData struct {
+	Username string
+	Title    string
+	Body     string
+}
+
+type DigestItem struct {
+	Title string
+	Body  string
+}
+
+type DigestData struct {
+	Username string
+	Period   string // daily | weekly
+	Items    []DigestItem
+}
+
 type MailBuilder interface {
-	BuildWelcomeEmail(to string, username string) (subject string, body string, err error)
-	// BuildResetPasswordEmail(to string, token string) (subject string, body string, err error)
+	Render(name Template, opts RenderOptions, data any) (Message, error)
+	BuildWelcomeEmail(locale, username string) (Message, error)
 }
@@ -0,0 +1,21 @@
+package mailer
+
+// Fixtures — тестовые данные для каждого шаблона (используется cmd/preview).
+func Fixtures() map[Template]any {
+	return map[Template]any{
+		TemplateWelcome: WelcomeData{Username: "aragorn<script>alert(1)</script>"},
+		TemplateEvent: EventData{
+			Username: "aragorn",
+			Title:    "Level up!",
+			Body:     "You reached level 5.",
+		},
+		TemplateDigest: DigestData{
+			Username: "aragorn",
+			Period:   "daily",
+			Items: []DigestItem{
+				{Title: "Quest completed", Body: "Morning run"},
+				{Title: "Quest completed", Body: "Read 20 pages"},
+			},
+		},
+	}
+}
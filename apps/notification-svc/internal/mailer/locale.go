@@ -0,0 +1,117 @@
+package mailer
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+type catalog map[string]string
+
+func loadCatalogs() (map[string]catalog, error) {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]catalog, len(entries))
+	for _, e := range entries {
+		b, err := localeFS.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var c catalog
+		if err := json.Unmarshal(b, &c); err != nil {
+			return nil, fmt.Errorf("locale %s: %w", e.Name(), err)
+		}
+		out[strings.TrimSuffix(e.Name(), ".json")] = c
+	}
+
+	if _, ok := out[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("default locale %q is missing", DefaultLocale)
+	}
+	return out, nil
+}
+
+// resolveLocale подбирает доступную локаль: "uk-UA" -> "uk-ua" -> "uk" -> DefaultLocale.
+func resolveLocale(catalogs map[string]catalog, locale string) string {
+	l := strings.ReplaceAll(strings.TrimSpace(strings.ToLower(locale)), "_", "-")
+	if _, ok := catalogs[l]; ok {
+		return l
+	}
+	if i := strings.Index(l, "-"); i > 0 {
+		if _, ok := catalogs[l[:i]]; ok {
+			return l[:i]
+		}
+	}
+	return DefaultLocale
+}
+
+func translator(catalogs map[string]catalog, locale string) func(key string, args ...any) string {
+	return func(key string, args ...any) string {
+		msg, ok := catalogs[locale][key]
+		if !ok {
+			msg, ok = catalogs[DefaultLocale][key]
+		}
+		if !ok {
+			return key
+		}
+		if len(args) > 0 {
+			return fmt.Sprintf(msg, args...)
+		}
+		return msg
+	}
+}
+
+// pluralTranslator — t для строк с числом: ключ key.<форма> по правилам CLDR
+// локали (one/few/many/other), n подставляется первым аргументом.
+func pluralTranslator(catalogs map[string]catalog, locale string) func(key string, n int, args ...any) string {
+	t := translator(catalogs, locale)
+	return func(key string, n int, args ...any) string {
+		args = append([]any{n}, args...)
+		k := key + "." + pluralForm(locale, n)
+		if _, ok := catalogs[locale][k]; ok {
+			return t(k, args...)
+		}
+		// локаль без этой формы — берём английскую строку целиком
+		return translator(catalogs, DefaultLocale)(key+"."+pluralForm(DefaultLocale, n), args...)
+	}
+}
+
+func pluralForm(locale string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch locale {
+	case "ru", "uk":
+		switch mod10, mod100 := n%10, n%100; {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	}
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func localeNames(catalogs map[string]catalog) []string {
+	out := make([]string, 0, len(catalogs))
+	for l := range catalogs {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,229 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/textproto"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"gopkg.in/gomail.v2"
+)
+
+var ErrPoolClosed = errors.New("mailer: pool closed")
+
+type Dialer interface {
+	Dial() (gomail.SendCloser, error)
+}
+
+type PoolConfig struct {
+	// MaxConns — сколько SMTP-сессий к провайдеру держим одновременно.
+	MaxConns int
+	// IdleTimeout — через сколько простоя сессия закрывается (QUIT).
+	IdleTimeout time.Duration
+	// MaxPerConn — после скольких писем сессия пересоздаётся; 0 — без лимита.
+	MaxPerConn int
+	// RateLimit — писем в секунду на провайдера; 0 — без лимита.
+	RateLimit rate.Limit
+	RateBurst int
+}
+
+type smtpConn struct {
+	sc       gomail.SendCloser
+	sent     int
+	lastUsed time.Time
+}
+
+// Pool держит keep-alive SMTP-сессии к одному провайдеру.
+// Упавшая на переиспользованной сессии отправка повторяется один раз на свежей.
+type Pool struct {
+	dialer  Dialer
+	cfg     PoolConfig
+	sem     chan struct{}
+	limiter *rate.Limiter
+
+	mu     sync.Mutex
+	idle   []*smtpConn
+	closed bool
+	done   chan struct{}
+}
+
+func NewPool(d Dialer, cfg PoolConfig) *Pool {
+	if cfg.MaxConns <= 0 {
+		cfg.MaxConns = 1
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 30 * time.Second
+	}
+
+	limit := rate.Inf
+	if cfg.RateLimit > 0 {
+		limit = cfg.RateLimit
+	}
+	burst := cfg.RateBurst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	p := &Pool{
+		dialer:  d,
+		cfg:     cfg,
+		sem:     make(chan struct{}, cfg.MaxConns),
+		limiter: rate.NewLimiter(limit, burst),
+		done:    make(chan struct{}),
+	}
+	go p.reap()
+	return p
+}
+
+func (p *Pool) Send(ctx context.Context, m *gomail.Message) error {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.sem }()
+
+	c, reused, err := p.get()
+	if err != nil {
+		return err
+	}
+
+	err = p.send(c, m)
+	if err != nil && reused && retryable(err) {
+		// сессия могла протухнуть на стороне сервера — пробуем на новой
+		_ = c.sc.Close()
+		if c, err = p.dial(); err != nil {
+			return err
+		}
+		err = p.send(c, m)
+	}
+	if err != nil {
+		// после ошибки транзакция в неизвестном состоянии (RSET gomail не умеет)
+		_ = c.sc.Close()
+		return err
+	}
+
+	p.put(c)
+	return nil
+}
+
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	close(p.done)
+
+	var firstErr error
+	for _, c := range idle {
+		if err := c.sc.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// send пробрасывает исходную ошибку SMTP-клиента: gomail.Send теряет её тип.
+func (p *Pool) send(c *smtpConn, m *gomail.Message) error {
+	var cause error
+	err := gomail.Send(gomail.SendFunc(func(from string, to []string, msg io.WriterTo) error {
+		cause = c.sc.Send(from, to, msg)
+		return cause
+	}), m)
+	if cause != nil {
+		return cause
+	}
+	return err
+}
+
+func (p *Pool) get() (*smtpConn, bool, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, false, ErrPoolClosed
+	}
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return c, true, nil
+	}
+	p.mu.Unlock()
+
+	c, err := p.dial()
+	return c, false, err
+}
+
+func (p *Pool) dial() (*smtpConn, error) {
+	sc, err := p.dialer.Dial()
+	if err != nil {
+		return nil, err
+	}
+	return &smtpConn{sc: sc}, nil
+}
+
+func (p *Pool) put(c *smtpConn) {
+	c.sent++
+	c.lastUsed = time.Now()
+
+	p.mu.Lock()
+	if p.closed || (p.cfg.MaxPerConn > 0 && c.sent >= p.cfg.MaxPerConn) {
+		p.mu.Unlock()
+		_ = c.sc.Close()
+		return
+	}
+	p.idle = append(p.idle, c)
+	p.mu.Unlock()
+}
+
+func (p *Pool) reap() {
+	t := time.NewTicker(p.cfg.IdleTimeout / 2)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-t.C:
+		}
+
+		cutoff := time.Now().Add(-p.cfg.IdleTimeout)
+		var stale []*smtpConn
+
+		p.mu.Lock()
+		keep := p.idle[:0]
+		for _, c := range p.idle {
+			if c.lastUsed.Before(cutoff) {
+				stale = append(stale, c)
+			} else {
+				keep = append(keep, c)
+			}
+		}
+		p.idle = keep
+		p.mu.Unlock()
+
+		for _, c := range stale {
+			_ = c.sc.Close()
+		}
+	}
+}
+
+// retryable — сетевые ошибки (обрыв, таймаут сессии). Ответ сервера 4xx/5xx
+// повторять на новой сессии бессмысленно.
+func retryable(err error) bool {
+	var tp *textproto.Error
+	return !errors.As(err, &tp)
+}
@@ -1,22 +1,114 @@
 package mailer
 
-import "gopkg.in/gomail.v2"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+	"gopkg.in/gomail.v2"
+)
 
 type MailSender struct {
-	dialer *gomail.Dialer
-	from   string
+	pool *Pool
+	from string
+	ids  *ulid.ULIDGenerator
+}
+
+func New(pool *Pool, from string) *MailSender {
+	return &MailSender{pool: pool, from: from, ids: ulid.NewULIDGenerator()}
+}
+
+// Envelope — одно письмо для SendBulk.
+type Envelope struct {
+	To      string
+	Message Message
+}
+
+// Result — исход отправки одного Envelope.
+type Result struct {
+	MessageID string
+	Err       error
 }
 
-func New(dialer *gomail.Dialer, from string) *MailSender {
-	return &MailSender{dialer: dialer, from: from}
+// Send отправляет письмо и возвращает его Message-ID, по которому потом
+// можно сопоставить bounce/complaint от провайдера.
+func (s *MailSender) Send(ctx context.Context, to string, msg Message) (string, error) {
+	m, id, err := s.build(to, msg)
+	if err != nil {
+		return "", err
+	}
+	if err := s.pool.Send(ctx, m); err != nil {
+		return "", err
+	}
+	return id, nil
 }
 
-func (s *MailSender) Send(to, subject, body string) error {
+// SendBulk рассылает пачку писем через пул. Одновременно в работе не больше
+// MaxConns писем пула, ошибка одного письма не прерывает остальные.
+// Результаты в порядке batch.
+func (s *MailSender) SendBulk(ctx context.Context, batch []Envelope) []Result {
+	out := make([]Result, len(batch))
+	sem := make(chan struct{}, s.pool.cfg.MaxConns)
+
+	var wg sync.WaitGroup
+	for i, e := range batch {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(batch); j++ {
+				out[j] = Result{Err: ctx.Err()}
+			}
+			wg.Wait()
+			return out
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			id, err := s.Send(ctx, e.To, e.Message)
+			out[i] = Result{MessageID: id, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	return out
+}
+
+func (s *MailSender) Close() error { return s.pool.Close() }
+
+func (s *MailSender) build(to string, msg Message) (*gomail.Message, string, error) {
+	id, err := s.messageID()
+	if err != nil {
+		return nil, "", err
+	}
+
 	m := gomail.NewMessage()
 	m.SetHeader("From", s.from)
 	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/html", body)
+	m.SetHeader("Subject", msg.Subject)
+	m.SetHeader("Message-ID", id)
+	if msg.UnsubscribeURL != "" {
+		m.SetHeader("List-Unsubscribe", "<"+msg.UnsubscribeURL+">")
+		m.SetHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	if msg.Text != "" {
+		m.SetBody("text/plain", msg.Text)
+		m.AddAlternative("text/html", msg.HTML)
+	} else {
+		m.SetBody("text/html", msg.HTML)
+	}
+	return m, id, nil
+}
 
-	return s.dialer.DialAndSend(m)
+func (s *MailSender) messageID() (string, error) {
+	id, err := s.ids.New()
+	if err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if i := strings.LastIndex(s.from, "@"); i >= 0 {
+		domain = strings.TrimSuffix(s.from[i+1:], ">")
+	}
+	return fmt.Sprintf("<%s@%s>", id, domain), nil
 }
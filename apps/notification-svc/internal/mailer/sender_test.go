@@ -0,0 +1,92 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/gomail.v2"
+)
+
+// fakeSMTP считает одновременные отправки и отбивает адреса из reject.
+type fakeSMTP struct {
+	reject   map[string]bool
+	inflight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (f *fakeSMTP) Dial() (gomail.SendCloser, error) { return f, nil }
+
+func (f *fakeSMTP) Send(_ string, to []string, msg io.WriterTo) error {
+	n := f.inflight.Add(1)
+	defer f.inflight.Add(-1)
+	for {
+		p := f.peak.Load()
+		if n <= p || f.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	if f.reject[to[0]] {
+		return &textproto.Error{Code: 550, Msg: "mailbox unavailable"}
+	}
+	_, err := msg.WriteTo(io.Discard)
+	return err
+}
+
+func (f *fakeSMTP) Close() error { return nil }
+
+func TestSendBulk(t *testing.T) {
+	smtp := &fakeSMTP{reject: map[string]bool{"bad@example.com": true}}
+	pool := NewPool(smtp, PoolConfig{MaxConns: 3})
+	defer pool.Close()
+	s := New(pool, "Life RPG <noreply@example.com>")
+
+	var batch []Envelope
+	for i := range 10 {
+		batch = append(batch, Envelope{To: fmt.Sprintf("u%d@example.com", i), Message: Message{Subject: "hi", HTML: "<p>hi</p>"}})
+	}
+	batch[4].To = "bad@example.com"
+
+	res := s.SendBulk(context.Background(), batch)
+	if len(res) != len(batch) {
+		t.Fatalf("got %d results, want %d", len(res), len(batch))
+	}
+	ids := map[string]bool{}
+	for i, r := range res {
+		if i == 4 {
+			var tp *textproto.Error
+			if !errors.As(r.Err, &tp) || r.MessageID != "" {
+				t.Errorf("rejected envelope: got %+v", r)
+			}
+			continue
+		}
+		if r.Err != nil || r.MessageID == "" || ids[r.MessageID] {
+			t.Errorf("envelope %d: got %+v", i, r)
+		}
+		ids[r.MessageID] = true
+	}
+	if p := smtp.peak.Load(); p > 3 {
+		t.Fatalf("%d sends in flight, pool allows 3", p)
+	}
+}
+
+func TestSendBulkCancelled(t *testing.T) {
+	pool := NewPool(&fakeSMTP{}, PoolConfig{MaxConns: 1})
+	defer pool.Close()
+	s := New(pool, "noreply@example.com")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res := s.SendBulk(ctx, make([]Envelope, 3))
+	for i, r := range res {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("envelope %d: got %+v", i, r)
+		}
+	}
+}
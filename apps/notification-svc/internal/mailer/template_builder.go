@@ -0,0 +1,109 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+type TemplateBuilder struct {
+	html     map[Template]*htmltemplate.Template
+	text     map[Template]*texttemplate.Template
+	catalogs map[string]catalog
+}
+
+// заглушки, чтобы шаблоны распарсились; реальные функции подставляются в Render под локаль.
+var stubFuncs = map[string]any{
+	"t":           func(string, ...any) string { return "" },
+	"tn":          func(string, int, ...any) string { return "" },
+	"lang":        func() string { return "" },
+	"unsubscribe": func() string { return "" },
+}
+
+func NewTemplateBuilder() (*TemplateBuilder, error) {
+	catalogs, err := loadCatalogs()
+	if err != nil {
+		return nil, err
+	}
+
+	b := &TemplateBuilder{
+		html:     make(map[Template]*htmltemplate.Template, len(Templates)),
+		text:     make(map[Template]*texttemplate.Template, len(Templates)),
+		catalogs: catalogs,
+	}
+
+	for _, name := range Templates {
+		h, err := htmltemplate.New("layout.html.tmpl").
+			Funcs(stubFuncs).
+			ParseFS(templateFS, "templates/layout.html.tmpl", fmt.Sprintf("templates/%s.html.tmpl", name))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s html: %w", name, err)
+		}
+		t, err := texttemplate.New("layout.txt.tmpl").
+			Funcs(stubFuncs).
+			ParseFS(templateFS, "templates/layout.txt.tmpl", fmt.Sprintf("templates/%s.txt.tmpl", name))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s text: %w", name, err)
+		}
+		b.html[name] = h
+		b.text[name] = t
+	}
+
+	return b, nil
+}
+
+func (b *TemplateBuilder) Locales() []string { return localeNames(b.catalogs) }
+
+func (b *TemplateBuilder) Render(name Template, opts RenderOptions, data any) (Message, error) {
+	h, ok := b.html[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown template %q", name)
+	}
+
+	lang := resolveLocale(b.catalogs, opts.Locale)
+	funcs := map[string]any{
+		"t":           translator(b.catalogs, lang),
+		"tn":          pluralTranslator(b.catalogs, lang),
+		"lang":        func() string { return lang },
+		"unsubscribe": func() string { return opts.UnsubscribeURL },
+	}
+
+	hc, err := h.Clone()
+	if err != nil {
+		return Message{}, err
+	}
+	tc, err := b.text[name].Clone()
+	if err != nil {
+		return Message{}, err
+	}
+	hc.Funcs(funcs)
+	tc.Funcs(funcs)
+
+	var subject, text, html bytes.Buffer
+	if err := tc.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := tc.ExecuteTemplate(&text, "layout.txt.tmpl", data); err != nil {
+		return Message{}, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := hc.ExecuteTemplate(&html, "layout.html.tmpl", data); err != nil {
+		return Message{}, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return Message{
+		Subject:        strings.TrimSpace(subject.String()),
+		HTML:           html.String(),
+		Text:           text.String(),
+		UnsubscribeURL: opts.UnsubscribeURL,
+	}, nil
+}
+
+func (b *TemplateBuilder) BuildWelcomeEmail(locale, username string) (Message, error) {
+	return b.Render(TemplateWelcome, RenderOptions{Locale: locale}, WelcomeData{Username: username})
+}
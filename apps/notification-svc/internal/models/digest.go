@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// DigestEntry — низкоприоритетное событие, отложенное до следующего дайджеста.
+type DigestEntry struct {
+	ID         string     `gorm:"primaryKey;size:26"`
+	UserID     string     `gorm:"size:36;not null;index:idx_digest_pending,priority:1"`
+	Kind       string     `gorm:"size:64;not null"`
+	Title      string     `gorm:"size:255;not null"`
+	Body       string     `gorm:"type:text"`
+	DigestedAt *time.Time `gorm:"index:idx_digest_pending,priority:2"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+func (DigestEntry) TableName() string { return "digest_entry" }
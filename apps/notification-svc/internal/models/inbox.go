@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+const ChannelInApp = "in_app"
+
+type InboxItem struct {
+	ID        string            `gorm:"primaryKey;size:26"`
+	UserID    string            `gorm:"size:36;not null;index:idx_inbox_user_read,priority:1"`
+	Kind      string            `gorm:"size:64;not null"`
+	Title     string            `gorm:"size:255;not null"`
+	Body      string            `gorm:"type:text"`
+	Data      map[string]string `gorm:"type:jsonb;serializer:json"`
+	ReadAt    *time.Time        `gorm:"index:idx_inbox_user_read,priority:2"`
+	CreatedAt time.Time         `gorm:"autoCreateTime"`
+}
+
+func (InboxItem) TableName() string { return "inbox_item" }
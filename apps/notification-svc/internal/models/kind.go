@@ -0,0 +1,46 @@
+package models
+
+const (
+	KindWelcome        = "welcome"
+	KindDigest         = "digest"
+	KindQuestCompleted = "quest.completed"
+	KindLevelUp        = "level.up"
+	KindStreakLost     = "streak.lost"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// Kinds — типы уведомлений, которые пользователь может настраивать.
+var Kinds = []string{
+	KindQuestCompleted,
+	KindLevelUp,
+	KindStreakLost,
+}
+
+var Channels = []string{
+	ChannelEmail,
+	ChannelInApp,
+}
+
+func KindPriority(kind string) Priority {
+	switch kind {
+	case KindWelcome:
+		return PriorityHigh
+	case KindQuestCompleted:
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
+
+// Transactional — служебные письма, которые нельзя отключить и которые
+// не ждут окончания тихих часов.
+func Transactional(kind string) bool {
+	return kind == KindWelcome
+}
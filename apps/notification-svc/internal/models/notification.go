@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	ChannelEmail = "email"
+)
+
+const (
+	StatusPending    = "pending"
+	StatusScheduled  = "scheduled"
+	StatusSending    = "sending" // забрано Retry, SMTP идёт вне транзакции
+	StatusSent       = "sent"
+	StatusFailed     = "failed"
+	StatusDead       = "dead"
+	StatusSuppressed = "suppressed"
+	StatusBounced    = "bounced"
+	StatusComplained = "complained"
+)
+
+type Notification struct {
+	ID                string     `gorm:"primaryKey;size:26"`
+	UserID            string     `gorm:"size:36;index;not null"`
+	Channel           string     `gorm:"size:16;not null"`
+	Kind              string     `gorm:"size:64;not null;default:''"`
+	Template          string     `gorm:"size:64;not null"`
+	Recipient         string     `gorm:"size:255;not null"`
+	Locale            string     `gorm:"size:16"`
+	Subject           string     `gorm:"size:255"`
+	BodyHTML          string     `gorm:"type:text"`
+	BodyText          string     `gorm:"type:text"`
+	Status            string     `gorm:"size:16;not null;index:idx_notification_due,priority:1"`
+	Attempts          int        `gorm:"not null;default:0"`
+	ProviderMessageID string     `gorm:"size:255;index"`
+	Error             string     `gorm:"type:text"`
+	NextAttemptAt     *time.Time `gorm:"index:idx_notification_due,priority:2"`
+	SentAt            *time.Time
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Notification) TableName() string { return "notification" }
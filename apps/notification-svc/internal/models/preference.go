@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// UserSettings — контакт пользователя и общие настройки доставки.
+// Заполняется из user.registered, настройки меняются через UpdatePreferences.
+type UserSettings struct {
+	UserID       string `gorm:"primaryKey;size:36"`
+	Email        string `gorm:"size:255;not null"`
+	Username     string `gorm:"size:64"`
+	Locale       string `gorm:"size:16;not null;default:'en'"`
+	Timezone     string `gorm:"size:64;not null;default:'UTC'"`
+	QuietStart   string `gorm:"size:5"` // "22:00", пусто — тихие часы выключены
+	QuietEnd     string `gorm:"size:5"` // "08:00"
+	DigestMode   string `gorm:"size:16;not null;default:'daily'"`
+	LastDigestAt *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (UserSettings) TableName() string { return "user_settings" }
+
+// Preference — явный opt-in/opt-out на пару (тип, канал). Нет строки — включено.
+type Preference struct {
+	UserID  string `gorm:"primaryKey;size:36"`
+	Kind    string `gorm:"primaryKey;size:64"`
+	Channel string `gorm:"primaryKey;size:16"`
+	Enabled bool   `gorm:"not null"`
+}
+
+func (Preference) TableName() string { return "preference" }
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	SuppressBounce    = "bounce"
+	SuppressComplaint = "complaint"
+)
+
+// Suppression — адрес, на который больше нельзя слать почту (жёсткий bounce
+// или жалоба). Отписки хранятся в Preference, а не здесь.
+type Suppression struct {
+	Email     string    `gorm:"primaryKey;size:255"`
+	Reason    string    `gorm:"size:16;not null"`
+	Detail    string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Suppression) TableName() string { return "suppression" }
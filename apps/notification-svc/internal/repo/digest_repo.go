@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"gorm.io/gorm"
+)
+
+type DigestRepo struct {
+	db *gorm.DB
+}
+
+func NewDigestRepo(db *gorm.DB) *DigestRepo { return &DigestRepo{db: db} }
+
+func (r *DigestRepo) Add(ctx context.Context, e *models.DigestEntry) error {
+	return db.FromContext(ctx, r.db).Create(e).Error
+}
+
+func (r *DigestRepo) Pending(ctx context.Context, userID string, limit int) ([]models.DigestEntry, error) {
+	var out []models.DigestEntry
+	err := db.FromContext(ctx, r.db).
+		Where("user_id = ? AND digested_at IS NULL", userID).
+		Order("id").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *DigestRepo) MarkDigested(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.FromContext(ctx, r.db).
+		Model(&models.DigestEntry{}).
+		Where("id IN ?", ids).
+		Update("digested_at", at).Error
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"gorm.io/gorm"
+)
+
+type InboxRepo struct {
+	db *gorm.DB
+}
+
+func NewInboxRepo(db *gorm.DB) *InboxRepo { return &InboxRepo{db: db} }
+
+func (r *InboxRepo) Create(ctx context.Context, it *models.InboxItem) error {
+	return db.FromContext(ctx, r.db).Create(it).Error
+}
+
+func (r *InboxRepo) ListByUser(ctx context.Context, userID string, unreadOnly bool, cursor string, limit int) ([]models.InboxItem, error) {
+	q := db.FromContext(ctx, r.db).Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+	if cursor != "" {
+		q = q.Where("id < ?", cursor)
+	}
+
+	var out []models.InboxItem
+	err := q.Order("id DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
+
+func (r *InboxRepo) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var n int64
+	err := db.FromContext(ctx, r.db).
+		Model(&models.InboxItem{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&n).Error
+	return n, err
+}
+
+// MarkRead помечает прочитанными указанные элементы; при пустом ids — все непрочитанные.
+func (r *InboxRepo) MarkRead(ctx context.Context, userID string, ids []string, at time.Time) (int64, error) {
+	q := db.FromContext(ctx, r.db).
+		Model(&models.InboxItem{}).
+		Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		q = q.Where("id IN ?", ids)
+	}
+
+	res := q.Update("read_at", at)
+	return res.RowsAffected, res.Error
+}
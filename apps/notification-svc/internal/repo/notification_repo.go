@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepo struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepo(db *gorm.DB) *NotificationRepo { return &NotificationRepo{db: db} }
+
+func (r *NotificationRepo) Create(ctx context.Context, n *models.Notification) error {
+	return db.FromContext(ctx, r.db).Create(n).Error
+}
+
+func (r *NotificationRepo) Save(ctx context.Context, n *models.Notification) error {
+	return db.FromContext(ctx, r.db).Save(n).Error
+}
+
+// ClaimDue забирает на отправку упавшие и отложенные (тихие часы) уведомления,
+// у которых подошло время, а также зависшие pending/sending — под упал между
+// записью и отправкой. Забранные строки переводятся в sending, сама отправка
+// идёт уже после коммита. Вызывать внутри транзакции: SKIP LOCKED позволяет
+// нескольким подам разбирать очередь без дублей.
+func (r *NotificationRepo) ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]models.Notification, error) {
+	q := db.FromContext(ctx, r.db)
+
+	var out []models.Notification
+	err := q.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("(status IN ? AND next_attempt_at <= ?) OR (status IN ? AND updated_at <= ?)",
+			[]string{models.StatusFailed, models.StatusScheduled}, now,
+			[]string{models.StatusPending, models.StatusSending}, staleBefore).
+		Order("created_at").
+		Limit(limit).
+		Find(&out).Error
+	if err != nil || len(out) == 0 {
+		return nil, err
+	}
+
+	ids := make([]string, len(out))
+	for i := range out {
+		ids[i] = out[i].ID
+		out[i].Status = models.StatusSending
+		out[i].UpdatedAt = now
+	}
+	err = q.Model(&models.Notification{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{"status": models.StatusSending, "updated_at": now}).Error
+	return out, err
+}
+
+// ListByUser возвращает уведомления пользователя от новых к старым.
+// ID — ULID, поэтому курсор пагинации — просто последний ID страницы.
+func (r *NotificationRepo) ListByUser(ctx context.Context, userID, cursor string, limit int) ([]models.Notification, error) {
+	q := db.FromContext(ctx, r.db).Where("user_id = ?", userID)
+	if cursor != "" {
+		q = q.Where("id < ?", cursor)
+	}
+
+	var out []models.Notification
+	err := q.Order("id DESC").Limit(limit).Find(&out).Error
+	return out, err
+}
+
+func (r *NotificationRepo) FindByProviderMessageID(ctx context.Context, id string) (models.Notification, bool, error) {
+	var out []models.Notification
+	err := db.FromContext(ctx, r.db).Where("provider_message_id = ?", id).Limit(1).Find(&out).Error
+	if err != nil || len(out) == 0 {
+		return models.Notification{}, false, err
+	}
+	return out[0], true, nil
+}
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PreferenceRepo struct {
+	db *gorm.DB
+}
+
+func NewPreferenceRepo(db *gorm.DB) *PreferenceRepo { return &PreferenceRepo{db: db} }
+
+// UpsertContact сохраняет email/username/locale, не трогая настройки пользователя.
+func (r *PreferenceRepo) UpsertContact(ctx context.Context, s models.UserSettings) error {
+	return db.FromContext(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email", "username", "locale", "updated_at"}),
+		}).
+		Create(&s).Error
+}
+
+func (r *PreferenceRepo) GetSettings(ctx context.Context, userID string) (models.UserSettings, bool, error) {
+	var s models.UserSettings
+	err := db.FromContext(ctx, r.db).First(&s, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.UserSettings{}, false, nil
+	}
+	return s, err == nil, err
+}
+
+// LockSettings блокирует строку настроек; занятая другим подом строка пропускается.
+func (r *PreferenceRepo) LockSettings(ctx context.Context, userID string) (models.UserSettings, bool, error) {
+	var out []models.UserSettings
+	err := db.FromContext(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("user_id = ?", userID).
+		Limit(1).
+		Find(&out).Error
+	if err != nil || len(out) == 0 {
+		return models.UserSettings{}, false, err
+	}
+	return out[0], true, nil
+}
+
+func (r *PreferenceRepo) SaveSettings(ctx context.Context, s *models.UserSettings) error {
+	return db.FromContext(ctx, r.db).Save(s).Error
+}
+
+func (r *PreferenceRepo) ListPreferences(ctx context.Context, userID string) ([]models.Preference, error) {
+	var out []models.Preference
+	err := db.FromContext(ctx, r.db).Where("user_id = ?", userID).Find(&out).Error
+	return out, err
+}
+
+func (r *PreferenceRepo) SetPreferences(ctx context.Context, prefs []models.Preference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return db.FromContext(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).
+		Create(&prefs).Error
+}
+
+func (r *PreferenceRepo) IsEnabled(ctx context.Context, userID, kind, channel string) (bool, error) {
+	var out []models.Preference
+	err := db.FromContext(ctx, r.db).
+		Where("user_id = ? AND kind = ? AND channel = ?", userID, kind, channel).
+		Limit(1).
+		Find(&out).Error
+	if err != nil {
+		return false, err
+	}
+	if len(out) == 0 {
+		return true, nil
+	}
+	return out[0].Enabled, nil
+}
+
+// digestDueSQL повторяет service.digestDue и quietUntil в SQL, чтобы планировщик
+// не перебирал пользователей, у которых слот ещё не наступил. Слот — последние
+// 09:00 по времени пользователя (для weekly — в понедельник): сдвиг на 9 часов
+// до date_trunc даёт предыдущий слот, если сегодняшний ещё не пришёл.
+const digestDueSQL = `
+COALESCE(last_digest_at, created_at) < (CASE digest_mode
+	WHEN @daily  THEN date_trunc('day',  (@now::timestamptz AT TIME ZONE timezone) - interval '9 hours')
+	WHEN @weekly THEN date_trunc('week', (@now::timestamptz AT TIME ZONE timezone) - interval '9 hours')
+END + interval '9 hours') AT TIME ZONE timezone
+AND NOT (
+	COALESCE(quiet_start, '') <> '' AND COALESCE(quiet_end, '') <> '' AND quiet_start::time <> quiet_end::time
+	AND CASE WHEN quiet_start::time < quiet_end::time
+		THEN (@now::timestamptz AT TIME ZONE timezone)::time >= quiet_start::time AND (@now::timestamptz AT TIME ZONE timezone)::time < quiet_end::time
+		ELSE (@now::timestamptz AT TIME ZONE timezone)::time >= quiet_start::time OR (@now::timestamptz AT TIME ZONE timezone)::time < quiet_end::time
+	END
+)`
+
+// ListDigestCandidates — пользователи, у которых наступил слот дайджеста вне
+// тихих часов и есть накопленные события. Окончательная проверка — под
+// блокировкой строки в сервисе.
+func (r *PreferenceRepo) ListDigestCandidates(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := db.FromContext(ctx, r.db).
+		Model(&models.UserSettings{}).
+		Where("digest_mode IN ?", []string{models.DigestDaily, models.DigestWeekly}).
+		Where(digestDueSQL, map[string]any{"now": now, "daily": models.DigestDaily, "weekly": models.DigestWeekly}).
+		Where("EXISTS (SELECT 1 FROM digest_entry d WHERE d.user_id = user_settings.user_id AND d.digested_at IS NULL)").
+		Order("user_id").
+		Limit(limit).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SuppressionRepo struct {
+	db *gorm.DB
+}
+
+func NewSuppressionRepo(db *gorm.DB) *SuppressionRepo { return &SuppressionRepo{db: db} }
+
+func normEmail(email string) string {
+	return strings.TrimSpace(strings.ToLower(email))
+}
+
+func (r *SuppressionRepo) Get(ctx context.Context, email string) (models.Suppression, bool, error) {
+	var s models.Suppression
+	err := db.FromContext(ctx, r.db).First(&s, "email = ?", normEmail(email)).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Suppression{}, false, nil
+	}
+	return s, err == nil, err
+}
+
+// Add заносит адрес в список; повторное событие перезаписывает причину.
+func (r *SuppressionRepo) Add(ctx context.Context, email, reason, detail string) error {
+	s := models.Suppression{Email: normEmail(email), Reason: reason, Detail: detail}
+	return db.FromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "detail", "updated_at"}),
+	}).Create(&s).Error
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+)
+
+type DigestSender interface {
+	SendDueDigests(ctx context.Context, limit int) (int, error)
+}
+
+type Digest struct {
+	svc      DigestSender
+	interval time.Duration
+	batch    int
+}
+
+func NewDigest(svc DigestSender, interval time.Duration, batch int) *Digest {
+	return &Digest{svc: svc, interval: interval, batch: batch}
+}
+
+func (d *Digest) Start(ctx context.Context) error {
+	t := time.NewTicker(d.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			n, err := d.svc.SendDueDigests(ctx, d.batch)
+			if err != nil {
+				slog.ErrorContext(ctx, "digest run failed", logx.Err(err))
+				continue
+			}
+			if n > 0 {
+				slog.InfoContext(ctx, "digests sent", "count", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+)
+
+type Retrier interface {
+	RetryDue(ctx context.Context, limit int) (int, error)
+}
+
+type Retry struct {
+	svc      Retrier
+	interval time.Duration
+	batch    int
+}
+
+func NewRetry(svc Retrier, interval time.Duration, batch int) *Retry {
+	return &Retry{svc: svc, interval: interval, batch: batch}
+}
+
+func (r *Retry) Start(ctx context.Context) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			n, err := r.svc.RetryDue(ctx, r.batch)
+			if err != nil {
+				slog.ErrorContext(ctx, "retry run failed", logx.Err(err))
+				continue
+			}
+			if n > 0 {
+				slog.InfoContext(ctx, "notifications resent", "count", n)
+			}
+		}
+	}
+}
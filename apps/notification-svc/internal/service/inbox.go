@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/inbox"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/repo"
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+)
+
+type InboxService struct {
+	Repo   *repo.InboxRepo
+	Fanout *inbox.Fanout
+	IDs    *ulid.ULIDGenerator
+}
+
+func NewInboxService(r *repo.InboxRepo, f *inbox.Fanout, g *ulid.ULIDGenerator) *InboxService {
+	return &InboxService{Repo: r, Fanout: f, IDs: g}
+}
+
+func (s *InboxService) Push(ctx context.Context, userID, kind, title, body string, data map[string]string) (models.InboxItem, error) {
+	if userID == "" || kind == "" || title == "" {
+		return models.InboxItem{}, errors.New("inbox: user id, kind and title are required")
+	}
+
+	id, err := s.IDs.New()
+	if err != nil {
+		return models.InboxItem{}, err
+	}
+
+	it := models.InboxItem{
+		ID:        id,
+		UserID:    userID,
+		Kind:      kind,
+		Title:     title,
+		Body:      body,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+	if err := s.Repo.Create(ctx, &it); err != nil {
+		return models.InboxItem{}, err
+	}
+
+	s.Fanout.Publish(ctx, it)
+	return it, nil
+}
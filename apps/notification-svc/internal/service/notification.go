@@ -1,20 +1,380 @@
 package service
 
-import "github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/mailer"
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/mailer"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/repo"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/unsubscribe"
+	"github.com/hassiimykyta/life-rpg/pkg/db"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/ulid"
+)
+
+const (
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+	// sendStaleAfter — через сколько pending/sending считается брошенным
+	// (под упал посреди отправки); с запасом больше таймаутов SMTP
+	sendStaleAfter = 10 * time.Minute
+
+	digestMaxItems = 50
+)
 
 type NotificationService struct {
-	Builder mailer.MailBuilder
-	Sender  *mailer.MailSender
+	Builder     mailer.MailBuilder
+	Sender      *mailer.MailSender
+	Repo        *repo.NotificationRepo
+	Prefs       *repo.PreferenceRepo
+	Digests     *repo.DigestRepo
+	Inbox       *InboxService
+	Suppress    *repo.SuppressionRepo
+	Unsub       *unsubscribe.Signer
+	IDs         *ulid.ULIDGenerator
+	Tx          *db.TxManager
+	MaxAttempts int
+}
+
+type Deps struct {
+	Builder       mailer.MailBuilder
+	Sender        *mailer.MailSender
+	Notifications *repo.NotificationRepo
+	Prefs         *repo.PreferenceRepo
+	Digests       *repo.DigestRepo
+	Inbox         *InboxService
+	Suppressions  *repo.SuppressionRepo
+	Unsubscribe   *unsubscribe.Signer
+	IDs           *ulid.ULIDGenerator
+	Tx            *db.TxManager
+	MaxAttempts   int
+}
+
+func NewNotificationService(d Deps) *NotificationService {
+	return &NotificationService{
+		Builder:     d.Builder,
+		Sender:      d.Sender,
+		Repo:        d.Notifications,
+		Prefs:       d.Prefs,
+		Digests:     d.Digests,
+		Inbox:       d.Inbox,
+		Suppress:    d.Suppressions,
+		Unsub:       d.Unsubscribe,
+		IDs:         d.IDs,
+		Tx:          d.Tx,
+		MaxAttempts: d.MaxAttempts,
+	}
+}
+
+func (n *NotificationService) SendWelcome(ctx context.Context, userID, to, username, locale string) error {
+	if err := n.Prefs.UpsertContact(ctx, models.UserSettings{
+		UserID:   userID,
+		Email:    to,
+		Username: username,
+		Locale:   locale,
+	}); err != nil {
+		slog.ErrorContext(ctx, "save contact failed", "user_id", userID, logx.Err(err))
+	}
+
+	msg, err := n.Builder.BuildWelcomeEmail(locale, username)
+	if err != nil {
+		return err
+	}
+	return n.sendEmail(ctx, &models.Notification{
+		UserID:    userID,
+		Kind:      models.KindWelcome,
+		Template:  string(mailer.TemplateWelcome),
+		Recipient: to,
+		Locale:    locale,
+	}, msg, time.Time{})
+}
+
+// Notify доставляет игровое событие по каналам с учётом настроек пользователя:
+// in-app сразу, email — сразу, после тихих часов или в дайджесте.
+func (n *NotificationService) Notify(ctx context.Context, userID, kind, title, body string, data map[string]string) error {
+	inApp, err := n.enabled(ctx, userID, kind, models.ChannelInApp)
+	if err != nil {
+		return err
+	}
+	if inApp {
+		if _, err := n.Inbox.Push(ctx, userID, kind, title, body, data); err != nil {
+			return err
+		}
+	}
+
+	email, err := n.enabled(ctx, userID, kind, models.ChannelEmail)
+	if err != nil || !email {
+		return err
+	}
+
+	s, ok, err := n.Prefs.GetSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !ok || s.Email == "" {
+		return nil
+	}
+
+	if models.KindPriority(kind) == models.PriorityLow && s.DigestMode != models.DigestOff {
+		id, err := n.IDs.New()
+		if err != nil {
+			return err
+		}
+		return n.Digests.Add(ctx, &models.DigestEntry{
+			ID:     id,
+			UserID: userID,
+			Kind:   kind,
+			Title:  title,
+			Body:   body,
+		})
+	}
+
+	msg, err := n.Builder.Render(mailer.TemplateEvent, mailer.RenderOptions{
+		Locale:         s.Locale,
+		UnsubscribeURL: n.unsubscribeURL(userID, s.Email, kind),
+	}, mailer.EventData{
+		Username: s.Username,
+		Title:    title,
+		Body:     body,
+	})
+	if err != nil {
+		return err
+	}
+
+	var notBefore time.Time
+	if until, quiet := quietUntil(s, time.Now()); quiet && !models.Transactional(kind) {
+		notBefore = until
+	}
+	return n.sendEmail(ctx, &models.Notification{
+		UserID:    userID,
+		Kind:      kind,
+		Template:  string(mailer.TemplateEvent),
+		Recipient: s.Email,
+		Locale:    s.Locale,
+	}, msg, notBefore)
+}
+
+func (n *NotificationService) enabled(ctx context.Context, userID, kind, channel string) (bool, error) {
+	if models.Transactional(kind) {
+		return true, nil
+	}
+	return n.Prefs.IsEnabled(ctx, userID, kind, channel)
+}
+
+// unsubscribeURL — ссылка отписки для несервисных писем; для сервисных пусто.
+func (n *NotificationService) unsubscribeURL(userID, email, kind string) string {
+	if models.Transactional(kind) {
+		return ""
+	}
+	u, err := n.Unsub.URL(unsubscribe.Claims{UserID: userID, Email: email, Kind: kind})
+	if err != nil {
+		slog.Error("build unsubscribe url failed", "user_id", userID, logx.Err(err))
+		return ""
+	}
+	return u
 }
 
-func NewNotificationService(b mailer.MailBuilder, s *mailer.MailSender) *NotificationService {
-	return &NotificationService{Builder: b, Sender: s}
+// sendEmail пишет уведомление в лог до отправки, чтобы упавшие письма
+// можно было переотправить из scheduler.Retry. Если задан notBefore,
+// письмо откладывается и уйдёт тем же планировщиком.
+// В rec заполнены адресат, тип и шаблон; остальное проставляется здесь.
+// Вызывать вне транзакции: SMTP нельзя откатить.
+func (n *NotificationService) sendEmail(ctx context.Context, rec *models.Notification, msg mailer.Message, notBefore time.Time) error {
+	if err := n.enqueueEmail(ctx, rec, msg, notBefore); err != nil {
+		return err
+	}
+	if rec.Status == models.StatusScheduled {
+		return nil
+	}
+	return n.deliver(ctx, rec)
 }
 
-func (n *NotificationService) SendWelcome(to, username string) error {
-	subject, body, err := n.Builder.BuildWelcomeEmail(to, username)
+// enqueueEmail только записывает письмо в лог (pending или scheduled).
+// Pending-запись, которую не отправили сразу, подберёт Retry после sendStaleAfter.
+func (n *NotificationService) enqueueEmail(ctx context.Context, rec *models.Notification, msg mailer.Message, notBefore time.Time) error {
+	id, err := n.IDs.New()
 	if err != nil {
 		return err
 	}
-	return n.Sender.Send(to, subject, body)
+
+	rec.ID = id
+	rec.Channel = models.ChannelEmail
+	rec.Subject = msg.Subject
+	rec.BodyHTML = msg.HTML
+	rec.BodyText = msg.Text
+	rec.Status = models.StatusPending
+	if notBefore.After(time.Now()) {
+		rec.Status = models.StatusScheduled
+		rec.NextAttemptAt = &notBefore
+	}
+	return n.Repo.Create(ctx, rec)
+}
+
+// deliver отправляет письмо и сохраняет итог. Если сохранить статус не
+// удалось, запись останется pending/sending и уйдёт повторно после
+// sendStaleAfter: доставка at-least-once.
+func (n *NotificationService) deliver(ctx context.Context, rec *models.Notification) error {
+	sup, suppressed, err := n.Suppress.Get(ctx, rec.Recipient)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		rec.Status = models.StatusSuppressed
+		rec.Error = "suppressed: " + sup.Reason
+		rec.NextAttemptAt = nil
+		return n.Repo.Save(ctx, rec)
+	}
+
+	rec.Attempts++
+	msgID, sendErr := n.Sender.Send(ctx, rec.Recipient, mailer.Message{
+		Subject:        rec.Subject,
+		HTML:           rec.BodyHTML,
+		Text:           rec.BodyText,
+		UnsubscribeURL: n.unsubscribeURL(rec.UserID, rec.Recipient, rec.Kind),
+	})
+
+	now := time.Now()
+	switch {
+	case sendErr == nil:
+		rec.Status = models.StatusSent
+		rec.ProviderMessageID = msgID
+		rec.Error = ""
+		rec.SentAt = &now
+		rec.NextAttemptAt = nil
+	case rec.Attempts >= n.MaxAttempts:
+		rec.Status = models.StatusDead
+		rec.Error = sendErr.Error()
+		rec.NextAttemptAt = nil
+	default:
+		next := now.Add(backoff(rec.Attempts))
+		rec.Status = models.StatusFailed
+		rec.Error = sendErr.Error()
+		rec.NextAttemptAt = &next
+	}
+
+	if err := n.Repo.Save(ctx, rec); err != nil {
+		slog.ErrorContext(ctx, "save notification status failed", "id", rec.ID, "status", rec.Status, logx.Err(err))
+		return err
+	}
+	return sendErr
+}
+
+// RetryDue переотправляет упавшие, отложенные и зависшие уведомления. Строки
+// забираются короткой транзакцией, SMTP идёт после коммита без блокировок.
+func (n *NotificationService) RetryDue(ctx context.Context, limit int) (int, error) {
+	var due []models.Notification
+	err := n.Tx.Do(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		var err error
+		due, err = n.Repo.ClaimDue(ctx, now, now.Add(-sendStaleAfter), limit)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range due {
+		if err := n.deliver(ctx, &due[i]); err == nil {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// SendDueDigests собирает накопленные события в письмо для пользователей,
+// у которых наступил слот дайджеста.
+func (n *NotificationService) SendDueDigests(ctx context.Context, limit int) (int, error) {
+	users, err := n.Prefs.ListDigestCandidates(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, userID := range users {
+		ok, err := n.sendDigest(ctx, userID)
+		if err != nil {
+			slog.ErrorContext(ctx, "send digest failed", "user_id", userID, logx.Err(err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendDigest в одной транзакции ставит письмо в лог и помечает события
+// отправленными; SMTP — после коммита. Если deliver не дошёл, письмо
+// уйдёт через Retry, дайджест повторно не собирается.
+func (n *NotificationService) sendDigest(ctx context.Context, userID string) (bool, error) {
+	sent := false
+	err := n.Tx.Do(ctx, func(ctx context.Context) error {
+		s, ok, err := n.Prefs.LockSettings(ctx, userID)
+		if err != nil || !ok {
+			return err
+		}
+		now := time.Now()
+		if !digestDue(s, now) {
+			return nil
+		}
+		if _, quiet := quietUntil(s, now); quiet {
+			return nil
+		}
+
+		entries, err := n.Digests.Pending(ctx, userID, digestMaxItems)
+		if err != nil || len(entries) == 0 {
+			return err
+		}
+
+		data := mailer.DigestData{Username: s.Username, Period: s.DigestMode}
+		ids := make([]string, 0, len(entries))
+		for _, e := range entries {
+			data.Items = append(data.Items, mailer.DigestItem{Title: e.Title, Body: e.Body})
+			ids = append(ids, e.ID)
+		}
+
+		msg, err := n.Builder.Render(mailer.TemplateDigest, mailer.RenderOptions{
+			Locale:         s.Locale,
+			UnsubscribeURL: n.unsubscribeURL(userID, s.Email, models.KindDigest),
+		}, data)
+		if err != nil {
+			return err
+		}
+		rec := &models.Notification{
+			UserID:    userID,
+			Kind:      models.KindDigest,
+			Template:  string(mailer.TemplateDigest),
+			Recipient: s.Email,
+			Locale:    s.Locale,
+		}
+		if err := n.enqueueEmail(ctx, rec, msg, time.Time{}); err != nil {
+			return err
+		}
+
+		if err := n.Digests.MarkDigested(ctx, ids, now); err != nil {
+			return err
+		}
+		s.LastDigestAt = &now
+		if err := n.Prefs.SaveSettings(ctx, &s); err != nil {
+			return err
+		}
+		db.AfterCommit(ctx, func(ctx context.Context) {
+			sent = true
+			// ошибка доставки не откатывает дайджест: письмо уже в логе и уйдёт ретраем
+			_ = n.deliver(ctx, rec)
+		})
+		return nil
+	})
+	return sent, err
+}
+
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << (attempt - 1)
+	if d <= 0 || d > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return d
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+)
+
+// дайджест уходит в 09:00 по времени пользователя
+const digestHour = 9
+
+func location(tz string) *time.Location {
+	if tz == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ParseClock разбирает "HH:MM" в минуты от полуночи.
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatClock — обратное к ParseClock, всегда "HH:MM" с ведущими нулями.
+func FormatClock(min int) string {
+	return fmt.Sprintf("%02d:%02d", min/60, min%60)
+}
+
+// quietUntil возвращает момент окончания тихих часов, если now в них попадает.
+func quietUntil(s models.UserSettings, now time.Time) (time.Time, bool) {
+	if s.QuietStart == "" || s.QuietEnd == "" {
+		return time.Time{}, false
+	}
+	start, err := ParseClock(s.QuietStart)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := ParseClock(s.QuietEnd)
+	if err != nil || start == end {
+		return time.Time{}, false
+	}
+
+	local := now.In(location(s.Timezone))
+	cur := local.Hour()*60 + local.Minute()
+	// время на часах, а не смещение от полуночи: в день перевода часов
+	// от полуночи до 08:00 проходит 7 или 9 часов
+	endAt := func(days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, end/60, end%60, 0, 0, local.Location())
+	}
+
+	switch {
+	case start < end && cur >= start && cur < end:
+		return endAt(0), true
+	case start > end && cur >= start:
+		return endAt(1), true
+	case start > end && cur < end:
+		return endAt(0), true
+	}
+	return time.Time{}, false
+}
+
+// digestDue — прошёл ли очередной слот дайджеста с момента последней отправки.
+func digestDue(s models.UserSettings, now time.Time) bool {
+	local := now.In(location(s.Timezone))
+	anchor := time.Date(local.Year(), local.Month(), local.Day(), digestHour, 0, 0, 0, local.Location())
+
+	var period int
+	switch s.DigestMode {
+	case models.DigestDaily:
+		period = 1
+	case models.DigestWeekly:
+		period = 7
+		// неделя начинается с понедельника
+		anchor = anchor.AddDate(0, 0, -((int(anchor.Weekday()) + 6) % 7))
+	default:
+		return false
+	}
+
+	if local.Before(anchor) {
+		anchor = anchor.AddDate(0, 0, -period)
+	}
+
+	last := s.CreatedAt
+	if s.LastDigestAt != nil {
+		last = *s.LastDigestAt
+	}
+	return last.Before(anchor)
+}
@@ -0,0 +1,37 @@
+package service
+
+import "testing"
+
+func TestParseClock(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want int
+	}{
+		{"00:00", 0},
+		{"07:05", 7*60 + 5},
+		{"7:05", 7*60 + 5},
+		{"23:59", 23*60 + 59},
+	} {
+		got, err := ParseClock(tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("%q: got %d, %v; want %d", tc.in, got, err, tc.want)
+		}
+	}
+
+	for _, in := range []string{
+		"", "22:0x", "+1:+1", "7:5 junk", "7:5", "24:00", "12:60",
+		"-1:30", "12:30:00", " 12:30", "12:30 ", "1230", "12.30",
+	} {
+		if got, err := ParseClock(in); err == nil {
+			t.Errorf("%q: want error, got %d", in, got)
+		}
+	}
+}
+
+func TestFormatClock(t *testing.T) {
+	for in, want := range map[int]string{0: "00:00", 65: "01:05", 23*60 + 59: "23:59"} {
+		if got := FormatClock(in); got != want {
+			t.Errorf("%d: got %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/models"
+	"github.com/hassiimykyta/life-rpg/apps/notification-svc/internal/unsubscribe"
+)
+
+const (
+	DeliveryEventBounce    = "bounce"
+	DeliveryEventComplaint = "complaint"
+)
+
+// Unsubscribe применяет токен из письма. Отписка от типа выключает email для него,
+// от дайджеста — email для всех низкоприоритетных типов. Адрес целиком глушат
+// только жёсткий bounce и жалоба (HandleDeliveryEvent).
+func (n *NotificationService) Unsubscribe(ctx context.Context, token string, dryRun bool) (unsubscribe.Claims, error) {
+	c, err := n.Unsub.Parse(token)
+	if err != nil || dryRun {
+		return c, err
+	}
+
+	switch c.Kind {
+	case models.KindDigest:
+		var prefs []models.Preference
+		for _, kind := range models.Kinds {
+			if models.KindPriority(kind) == models.PriorityLow {
+				prefs = append(prefs, models.Preference{UserID: c.UserID, Kind: kind, Channel: models.ChannelEmail})
+			}
+		}
+		return c, n.Prefs.SetPreferences(ctx, prefs)
+	default:
+		return c, n.Prefs.SetPreferences(ctx, []models.Preference{
+			{UserID: c.UserID, Kind: c.Kind, Channel: models.ChannelEmail},
+		})
+	}
+}
+
+// HandleDeliveryEvent обрабатывает bounce/complaint от почтового провайдера.
+// Жёсткий bounce и жалоба добавляют адрес в suppression-лист.
+func (n *NotificationService) HandleDeliveryEvent(ctx context.Context, typ, email, messageID string, permanent bool, reason string) error {
+	var status string
+	switch typ {
+	case DeliveryEventBounce:
+		status = models.StatusBounced
+		if permanent {
+			if err := n.Suppress.Add(ctx, email, models.SuppressBounce, reason); err != nil {
+				return err
+			}
+		}
+	case DeliveryEventComplaint:
+		status = models.StatusComplained
+		if err := n.Suppress.Add(ctx, email, models.SuppressComplaint, reason); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown delivery event %q", typ)
+	}
+
+	if messageID == "" {
+		return nil
+	}
+	rec, ok, err := n.Repo.FindByProviderMessageID(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		slog.WarnContext(ctx, "delivery event for unknown message", "type", typ, "message_id", messageID)
+		return nil
+	}
+	rec.Status = status
+	rec.Error = fmt.Sprintf("%s: %s", typ, reason)
+	return n.Repo.Save(ctx, &rec)
+}
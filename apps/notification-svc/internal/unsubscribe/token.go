@@ -0,0 +1,75 @@
+package unsubscribe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidToken = errors.New("unsubscribe: invalid token")
+
+// Claims — что именно отписываем: тип письма или дайджест (models.KindDigest).
+type Claims struct {
+	UserID string `json:"u"`
+	Email  string `json:"e"`
+	Kind   string `json:"k"`
+}
+
+// Signer выпускает бессрочные HMAC-токены для one-click отписки (RFC 8058).
+type Signer struct {
+	secret  []byte
+	baseURL string
+}
+
+func NewSigner(secret, baseURL string) *Signer {
+	return &Signer{secret: []byte(secret), baseURL: baseURL}
+}
+
+func (s *Signer) Token(c Claims) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload)), nil
+}
+
+func (s *Signer) Parse(token string) (Claims, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.sign(payload)) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var c Claims
+	if err := json.Unmarshal(b, &c); err != nil || c.UserID == "" || c.Email == "" || c.Kind == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	return c, nil
+}
+
+// URL — ссылка для футера письма и заголовка List-Unsubscribe.
+func (s *Signer) URL(c Claims) (string, error) {
+	t, err := s.Token(c)
+	if err != nil {
+		return "", err
+	}
+	return s.baseURL + "?token=" + url.QueryEscape(t), nil
+}
+
+func (s *Signer) sign(payload string) []byte {
+	m := hmac.New(sha256.New, s.secret)
+	m.Write([]byte(payload))
+	return m.Sum(nil)
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS suppression;
+DROP TABLE IF EXISTS digest_entry;
+DROP TABLE IF EXISTS preference;
+DROP TABLE IF EXISTS user_settings;
+DROP TABLE IF EXISTS inbox_item;
+DROP TABLE IF EXISTS notification;
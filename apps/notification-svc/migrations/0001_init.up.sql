@@ -0,0 +1,80 @@
+-- IF NOT EXISTS: базы, созданные ещё GORM AutoMigrate, принимают миграцию без изменений
+CREATE TABLE IF NOT EXISTS notification (
+    id                  varchar(26)  PRIMARY KEY,
+    user_id             varchar(36)  NOT NULL,
+    channel             varchar(16)  NOT NULL,
+    kind                varchar(64)  NOT NULL DEFAULT '',
+    template            varchar(64)  NOT NULL,
+    recipient           varchar(255) NOT NULL,
+    locale              varchar(16),
+    subject             varchar(255),
+    body_html           text,
+    body_text           text,
+    status              varchar(16)  NOT NULL,
+    attempts            bigint       NOT NULL DEFAULT 0,
+    provider_message_id varchar(255),
+    error               text,
+    next_attempt_at     timestamptz,
+    sent_at             timestamptz,
+    created_at          timestamptz,
+    updated_at          timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_user_id ON notification (user_id);
+CREATE INDEX IF NOT EXISTS idx_notification_due ON notification (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_notification_provider_message_id ON notification (provider_message_id);
+
+CREATE TABLE IF NOT EXISTS inbox_item (
+    id         varchar(26)  PRIMARY KEY,
+    user_id    varchar(36)  NOT NULL,
+    kind       varchar(64)  NOT NULL,
+    title      varchar(255) NOT NULL,
+    body       text,
+    data       jsonb,
+    read_at    timestamptz,
+    created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_user_read ON inbox_item (user_id, read_at);
+
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id        varchar(36)  PRIMARY KEY,
+    email          varchar(255) NOT NULL,
+    username       varchar(64),
+    locale         varchar(16)  NOT NULL DEFAULT 'en',
+    timezone       varchar(64)  NOT NULL DEFAULT 'UTC',
+    quiet_start    varchar(5),
+    quiet_end      varchar(5),
+    digest_mode    varchar(16)  NOT NULL DEFAULT 'daily',
+    last_digest_at timestamptz,
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS preference (
+    user_id varchar(36) NOT NULL,
+    kind    varchar(64) NOT NULL,
+    channel varchar(16) NOT NULL,
+    enabled boolean     NOT NULL,
+    PRIMARY KEY (user_id, kind, channel)
+);
+
+CREATE TABLE IF NOT EXISTS digest_entry (
+    id          varchar(26)  PRIMARY KEY,
+    user_id     varchar(36)  NOT NULL,
+    kind        varchar(64)  NOT NULL,
+    title       varchar(255) NOT NULL,
+    body        text,
+    digested_at timestamptz,
+    created_at  timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_pending ON digest_entry (user_id, digested_at);
+
+CREATE TABLE IF NOT EXISTS suppression (
+    email      varchar(255) PRIMARY KEY,
+    reason     varchar(16)  NOT NULL,
+    detail     text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
@@ -0,0 +1,25 @@
+#!/usr/bin/env sh
+# Dev-CA и сертификаты сервисов для mTLS: ./docker/certs/gen.sh [out_dir]
+# SAN = имя сервиса в docker-compose; его же перечисляют в TLS_ALLOWED_PEERS.
+set -eu
+
+OUT="${1:-./var/certs}"
+DAYS=825
+mkdir -p "$OUT"
+
+if [ ! -f "$OUT/ca.pem" ]; then
+  openssl req -x509 -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+    -keyout "$OUT/ca-key.pem" -out "$OUT/ca.pem" -days "$DAYS" \
+    -subj "/CN=life-rpg dev CA"
+fi
+
+for svc in gateway auth-svc notification-svc; do
+  openssl req -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+    -keyout "$OUT/$svc-key.pem" -out "$OUT/$svc.csr" -subj "/CN=$svc"
+  printf "subjectAltName=DNS:%s,DNS:localhost\nextendedKeyUsage=serverAuth,clientAuth\n" "$svc" > "$OUT/$svc.ext"
+  openssl x509 -req -in "$OUT/$svc.csr" -CA "$OUT/ca.pem" -CAkey "$OUT/ca-key.pem" \
+    -CAcreateserial -out "$OUT/$svc.pem" -days "$DAYS" -extfile "$OUT/$svc.ext"
+  rm -f "$OUT/$svc.csr" "$OUT/$svc.ext"
+done
+
+echo "certs written to $OUT"
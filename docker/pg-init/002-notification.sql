@@ -0,0 +1,19 @@
+\echo '>>> INIT 002-notification.sql STARTED <<<'
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'notification_app') THEN
+    CREATE ROLE notification_app LOGIN PASSWORD 'notification';
+  ELSE
+    ALTER ROLE notification_app WITH LOGIN PASSWORD 'notification';
+  END IF;
+END$$;
+
+SELECT 'CREATE DATABASE notification_db OWNER notification_app'
+WHERE NOT EXISTS (SELECT 1 FROM pg_database WHERE datname = 'notification_db')\gexec
+
+\connect notification_db
+ALTER SCHEMA public OWNER TO notification_app;
+GRANT ALL ON SCHEMA public TO notification_app;
+
+\echo '>>> INIT 002-notification.sql FINISHED <<<'
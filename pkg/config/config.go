@@ -3,10 +3,9 @@ package config
 import (
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
-	"github.com/hassiimykyta/life-rpg/pkg/helpers"
 	"github.com/joho/godotenv"
 )
 
@@ -17,6 +16,8 @@ type loadCaps struct {
 	useStorage bool
 	useCache   bool
 	useSMTP    bool
+	useTracing bool
+	useTLS     bool
 }
 
 type Option func(*loadCaps)
@@ -27,23 +28,36 @@ func WithJWT() Option     { return func(c *loadCaps) { c.useJWT = true } }
 func WithStorage() Option { return func(c *loadCaps) { c.useStorage = true } }
 func WithCache() Option   { return func(c *loadCaps) { c.useCache = true } }
 func WithSMTP() Option    { return func(c *loadCaps) { c.useSMTP = true } }
+func WithTracing() Option { return func(c *loadCaps) { c.useTracing = true } }
+func WithTLS() Option     { return func(c *loadCaps) { c.useTLS = true } }
 
 type AppConfig struct {
 	Env             string
 	Host            string
 	Port            string
+	AdminPort       string
+	LogLevel        string
+	LogFormat       string
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
 	ShutdownTimeout time.Duration
+	// DrainDelay — пауза между not ready и остановкой серверов, чтобы балансировщик снял трафик
+	DrainDelay time.Duration
 }
 
 type DBConfig struct {
-	Driver      string
-	DSN         string
-	MaxOpen     int
-	MaxIdle     int
-	MaxIdleTime time.Duration
+	Driver        string
+	DSN           string
+	ReplicaDSNs   []string
+	MaxOpen       int
+	MaxIdle       int
+	MaxIdleTime   time.Duration
+	MaxLifetime   time.Duration
+	QueryTimeout  time.Duration
+	SlowThreshold time.Duration
+	// MigrateOnStart — применять миграции при старте; в prod лучше отдельным `migrate up`
+	MigrateOnStart bool
 }
 
 type CORSConfig struct {
@@ -63,8 +77,10 @@ type JWTConfig struct {
 }
 
 type StorageConfig struct {
-	Driver        string
-	LocalDir      string
+	Driver   string
+	LocalDir string
+	// SigningKey — HMAC для presigned-ссылок локального драйвера
+	SigningKey    string
 	PublicBaseURL string
 	PresignTTL    time.Duration
 	S3Bucket      string
@@ -76,15 +92,20 @@ type StorageConfig struct {
 }
 
 type CacheConfig struct {
-	Addr         string
-	Password     string
-	DB           int
-	DialTimeout  time.Duration
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	PoolSize     int
-	MinIdleConns int
-	TLSEnabled   bool
+	// Mode — standalone, sentinel или cluster
+	Mode             string
+	Addrs            []string
+	MasterName       string
+	Username         string
+	Password         string
+	SentinelPassword string
+	DB               int
+	DialTimeout      time.Duration
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	PoolSize         int
+	MinIdleConns     int
+	TLSEnabled       bool
 }
 
 type SMTPConfig struct {
@@ -92,6 +113,28 @@ type SMTPConfig struct {
 	Password string
 	Host     string
 	Port     int
+
+	PoolSize    int
+	IdleTimeout time.Duration
+	MaxPerConn  int
+	RateLimit   float64 // писем в секунду (0.5 — раз в две секунды), 0 — без лимита
+	RateBurst   int
+}
+
+type TracingConfig struct {
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+}
+
+type TLSConfig struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// AllowedPeers — SAN клиентских сертификатов, которым разрешено звать сервис
+	AllowedPeers   []string
+	ReloadInterval time.Duration
 }
 
 type Config struct {
@@ -102,15 +145,35 @@ type Config struct {
 	Storage *StorageConfig
 	Cache   *CacheConfig
 	SMTP    *SMTPConfig
+	Tracing *TracingConfig
+	TLS     *TLSConfig
 }
 
+// Значения по умолчанию для локального запуска, в prod запрещены.
+const (
+	devJWTSecret         = "dev_secret_change_me"
+	devStorageSigningKey = "dev_storage_key_change_me"
+)
+
+// Validate проверяет конфиг целиком и возвращает все найденные проблемы разом.
 func (c *Config) Validate(cap loadCaps) error {
-	if cap.useCORS && c.CORS != nil && c.App.Env == "prod" {
+	var errs []error
+	prod := c.App.Env == "prod"
+
+	if c.App.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT must be positive"))
+	}
+	if c.App.DrainDelay < 0 {
+		errs = append(errs, errors.New("SHUTDOWN_DRAIN_DELAY must not be negative"))
+	}
+
+	if cap.useCORS && c.CORS != nil && prod {
 		if len(c.CORS.AllowedOrigins) == 1 && c.CORS.AllowedOrigins[0] == "*" {
 			if c.CORS.AllowCredentials {
-				return fmt.Errorf("in prod, CORS_ALLOW_CREDENTIALS=true cannot be used with CORS_ALLOWED_ORIGINS=*")
+				errs = append(errs, errors.New("in prod, CORS_ALLOW_CREDENTIALS=true cannot be used with CORS_ALLOWED_ORIGINS=*"))
+			} else {
+				slog.Warn("prod with CORS_ALLOWED_ORIGINS=* (no credentials); consider whitelisting domains")
 			}
-			log.Println("⚠️  WARNING: prod + CORS_ALLOWED_ORIGINS=* (no credentials). Consider whitelisting domains.")
 		}
 	}
 
@@ -119,12 +182,78 @@ func (c *Config) Validate(cap loadCaps) error {
 			return errors.New("DB config required but missing (enable WithDB and provide envs)")
 		}
 		if c.DB.DSN == "" {
-			return errors.New("DB_DSN is required when DB is enabled")
+			errs = append(errs, errors.New("DB_DSN is required when DB is enabled"))
 		}
 		switch c.DB.Driver {
 		case "postgres", "pgx", "mysql":
 		default:
-			return fmt.Errorf("unsupported DB_DRIVER %q", c.DB.Driver)
+			errs = append(errs, fmt.Errorf("unsupported DB_DRIVER %q", c.DB.Driver))
+		}
+	}
+
+	if cap.useCache {
+		if c.Cache == nil {
+			return errors.New("cache config required but missing (enable WithCache and provide envs)")
+		}
+		if len(c.Cache.Addrs) == 0 {
+			errs = append(errs, errors.New("REDIS_ADDR is required when cache is enabled"))
+		}
+		switch c.Cache.Mode {
+		case "standalone":
+			if len(c.Cache.Addrs) > 1 {
+				errs = append(errs, errors.New("REDIS_ADDR must be a single address in standalone mode"))
+			}
+		case "sentinel":
+			if c.Cache.MasterName == "" {
+				errs = append(errs, errors.New("REDIS_MASTER_NAME is required when REDIS_MODE=sentinel"))
+			}
+		case "cluster":
+			if c.Cache.DB != 0 {
+				errs = append(errs, errors.New("REDIS_DB must be 0 when REDIS_MODE=cluster"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unsupported REDIS_MODE %q", c.Cache.Mode))
+		}
+	}
+
+	if cap.useStorage {
+		if c.Storage == nil {
+			return errors.New("storage config required but missing (enable WithStorage and provide envs)")
+		}
+		switch c.Storage.Driver {
+		case "local":
+			if c.Storage.LocalDir == "" {
+				errs = append(errs, errors.New("STORAGE_LOCAL_DIR is required when STORAGE_DRIVER=local"))
+			}
+			if prod && c.Storage.SigningKey == devStorageSigningKey {
+				errs = append(errs, errors.New("STORAGE_SIGNING_KEY must be changed from the dev default in prod"))
+			}
+		case "s3":
+			if c.Storage.S3Bucket == "" {
+				errs = append(errs, errors.New("S3_BUCKET is required when STORAGE_DRIVER=s3"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unsupported STORAGE_DRIVER %q", c.Storage.Driver))
+		}
+		if c.Storage.PresignTTL <= 0 {
+			errs = append(errs, errors.New("STORAGE_PRESIGN_TTL must be positive"))
+		}
+	}
+
+	if cap.useTracing && c.Tracing != nil && (c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1) {
+		errs = append(errs, errors.New("OTEL_SAMPLE_PERCENT must be between 0 and 100"))
+	}
+
+	if cap.useSMTP && c.SMTP != nil && c.SMTP.RateLimit < 0 {
+		errs = append(errs, errors.New("SMTP_RATE_LIMIT must not be negative"))
+	}
+
+	if cap.useTLS && c.TLS != nil {
+		if c.TLS.Enabled && (c.TLS.CertFile == "" || c.TLS.KeyFile == "" || c.TLS.CAFile == "") {
+			errs = append(errs, errors.New("TLS_CERT_FILE, TLS_KEY_FILE and TLS_CA_FILE are required when TLS_ENABLED=true"))
+		}
+		if !c.TLS.Enabled && prod {
+			slog.Warn("prod without TLS_ENABLED; service-to-service traffic is plaintext")
 		}
 	}
 
@@ -132,108 +261,172 @@ func (c *Config) Validate(cap loadCaps) error {
 		if c.JWT == nil {
 			return errors.New("JWT config required but missing (enable WithJWT and provide envs)")
 		}
-		if c.JWT.Secret == "" {
-			return errors.New("JWT_SECRET is required when JWT is enabled")
+		switch {
+		case c.JWT.Secret == "":
+			errs = append(errs, errors.New("JWT_SECRET is required when JWT is enabled"))
+		case prod && c.JWT.Secret == devJWTSecret:
+			errs = append(errs, errors.New("JWT_SECRET must be changed from the dev default in prod"))
+		case prod && len(c.JWT.Secret) < 32:
+			errs = append(errs, errors.New("JWT_SECRET must be at least 32 bytes in prod"))
+		}
+		if c.JWT.AccessTTL <= 0 || c.JWT.RefreshTTL <= 0 {
+			errs = append(errs, errors.New("JWT_ACCESS_TTL and JWT_REFRESH_TTL must be positive"))
 		}
 	}
 
-	return nil
-}
-func (c *Config) RedactedString() string {
-	return fmt.Sprintf(
-		"env=%s host=%s port=%s db.driver=%s db.maxOpen=%d db.maxIdle=%d",
-		c.App.Env, c.App.Host, c.App.Port, c.DB.Driver, c.DB.MaxOpen, c.DB.MaxIdle,
-	)
+	return errors.Join(errs...)
 }
 
+// Load собирает конфиг. Приоритет источников: переменные окружения (включая .env),
+// затем KEY_FILE для секретов, затем файл из CONFIG_FILE, затем значения по умолчанию.
+// Ошибки разбора и валидации возвращаются все сразу.
 func Load(opts ...Option) (*Config, error) {
 	_ = godotenv.Load()
+	return load(opts)
+}
+
+func load(opts []Option) (*Config, error) {
+	if err := loadSecretFiles(); err != nil {
+		return nil, err
+	}
+	if err := loadConfigFile(); err != nil {
+		return nil, err
+	}
 
 	var caps loadCaps
 	for _, o := range opts {
 		o(&caps)
 	}
 
+	var p parser
+	cfg := build(&p, caps)
+	if err := errors.Join(errors.Join(p.errs...), cfg.Validate(caps)); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+	return cfg, nil
+}
+
+// build читает секции, включённые в caps, из окружения.
+func build(p *parser, caps loadCaps) *Config {
 	cfg := &Config{
 		App: AppConfig{
-			Env:             helpers.GetEnv("APP_ENV", "dev"),
-			Host:            helpers.GetEnv("APP_HOST", "0.0.0.0"),
-			Port:            helpers.GetEnv("APP_PORT", "8080"),
-			ReadTimeout:     helpers.MustDur(helpers.GetEnv("READ_TIMEOUT", "10s"), 10*time.Second),
-			WriteTimeout:    helpers.MustDur(helpers.GetEnv("WRITE_TIMEOUT", "10s"), 10*time.Second),
-			IdleTimeout:     helpers.MustDur(helpers.GetEnv("IDLE_TIMEOUT", "60s"), 60*time.Second),
-			ShutdownTimeout: helpers.MustDur(helpers.GetEnv("SHUTDOWN_TIMEOUT", "10s"), 10*time.Second),
+			Env:             p.str("APP_ENV", "dev"),
+			Host:            p.str("APP_HOST", "0.0.0.0"),
+			Port:            p.str("APP_PORT", "8080"),
+			AdminPort:       p.str("ADMIN_PORT", "9100"),
+			LogLevel:        p.str("LOG_LEVEL", "info"),
+			LogFormat:       p.str("LOG_FORMAT", ""),
+			ReadTimeout:     p.dur("READ_TIMEOUT", 10*time.Second),
+			WriteTimeout:    p.dur("WRITE_TIMEOUT", 10*time.Second),
+			IdleTimeout:     p.dur("IDLE_TIMEOUT", 60*time.Second),
+			ShutdownTimeout: p.dur("SHUTDOWN_TIMEOUT", 10*time.Second),
+			DrainDelay:      p.dur("SHUTDOWN_DRAIN_DELAY", 0),
 		},
 	}
 
 	if caps.useDB {
 		cfg.DB = &DBConfig{
-			Driver:      helpers.GetEnv("DB_DRIVER", "postgres"),
-			DSN:         helpers.GetEnv("DB_DSN", ""),
-			MaxOpen:     helpers.MustInt(helpers.GetEnv("DB_MAX_OPEN", "20"), 20),
-			MaxIdle:     helpers.MustInt(helpers.GetEnv("DB_MAX_IDLE", "10"), 10),
-			MaxIdleTime: helpers.MustDur(helpers.GetEnv("DB_MAX_IDLE_TIME", "5m"), 5*time.Minute),
+			Driver:         p.str("DB_DRIVER", "postgres"),
+			DSN:            p.str("DB_DSN", ""),
+			ReplicaDSNs:    p.csv("DB_REPLICA_DSNS", ""),
+			MaxOpen:        p.int("DB_MAX_OPEN", 20),
+			MaxIdle:        p.int("DB_MAX_IDLE", 10),
+			MaxIdleTime:    p.dur("DB_MAX_IDLE_TIME", 5*time.Minute),
+			MaxLifetime:    p.dur("DB_MAX_LIFETIME", 30*time.Minute),
+			QueryTimeout:   p.dur("DB_QUERY_TIMEOUT", 5*time.Second),
+			SlowThreshold:  p.dur("DB_SLOW_THRESHOLD", 200*time.Millisecond),
+			MigrateOnStart: p.bool("DB_MIGRATE_ON_START", true),
 		}
 	}
 
 	if caps.useCORS {
 		cfg.CORS = &CORSConfig{
-			AllowedOrigins:   helpers.Csv(helpers.GetEnv("CORS_ALLOWED_ORIGINS", "*")),
-			AllowedMethods:   helpers.Csv(helpers.GetEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")),
-			AllowedHeaders:   helpers.Csv(helpers.GetEnv("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,X-CSRF-Token")),
-			ExposedHeaders:   helpers.Csv(helpers.GetEnv("CORS_EXPOSE_HEADERS", "")),
-			AllowCredentials: helpers.MustBool(helpers.GetEnv("CORS_ALLOW_CREDENTIALS", "true"), true),
-			MaxAge:           helpers.MustInt(helpers.GetEnv("CORS_MAX_AGE", "300"), 300),
+			AllowedOrigins:   p.csv("CORS_ALLOWED_ORIGINS", "*"),
+			AllowedMethods:   p.csv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+			AllowedHeaders:   p.csv("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,X-CSRF-Token"),
+			ExposedHeaders:   p.csv("CORS_EXPOSE_HEADERS", ""),
+			AllowCredentials: p.bool("CORS_ALLOW_CREDENTIALS", true),
+			MaxAge:           p.int("CORS_MAX_AGE", 300),
 		}
 	}
 
 	if caps.useJWT {
 		cfg.JWT = &JWTConfig{
-			Secret:     helpers.GetEnv("JWT_SECRET", "dev_secret_change_me"),
-			Issuer:     helpers.GetEnv("JWT_ISSUER", "app"),
-			AccessTTL:  helpers.MustDur(helpers.GetEnv("JWT_ACCESS_TTL", "15m"), 15*time.Minute),
-			RefreshTTL: helpers.MustDur(helpers.GetEnv("JWT_REFRESH_TTL", "720h"), 30*24*time.Hour),
+			Secret:     p.str("JWT_SECRET", devJWTSecret),
+			Issuer:     p.str("JWT_ISSUER", "app"),
+			AccessTTL:  p.dur("JWT_ACCESS_TTL", 15*time.Minute),
+			RefreshTTL: p.dur("JWT_REFRESH_TTL", 30*24*time.Hour),
 		}
 	}
 
 	if caps.useStorage {
 		cfg.Storage = &StorageConfig{
-			Driver:        helpers.GetEnv("STORAGE_DRIVER", "local"),
-			LocalDir:      helpers.GetEnv("STORAGE_LOCAL_DIR", "./var/media"),
-			PublicBaseURL: helpers.GetEnv("STORAGE_PUBLIC_BASE_URL", "http://localhost:8080"),
-			PresignTTL:    helpers.MustDur(helpers.GetEnv("STORAGE_PRESIGN_TTL", "10m"), 10*time.Minute),
-			S3Bucket:      helpers.GetEnv("S3_BUCKET", ""),
-			S3Region:      helpers.GetEnv("S3_REGION", ""),
-			S3Endpoint:    helpers.GetEnv("S3_ENDPOINT", ""),
-			S3AccessKey:   helpers.GetEnv("S3_ACCESS_KEY", ""),
-			S3SecretKey:   helpers.GetEnv("S3_SECRET_KEY", ""),
-			S3UsePath:     helpers.MustBool(helpers.GetEnv("S3_USE_PATH_STYLE", "true"), true),
+			Driver:        p.str("STORAGE_DRIVER", "local"),
+			LocalDir:      p.str("STORAGE_LOCAL_DIR", "./var/media"),
+			SigningKey:    p.str("STORAGE_SIGNING_KEY", devStorageSigningKey),
+			PublicBaseURL: p.str("STORAGE_PUBLIC_BASE_URL", "http://localhost:8080"),
+			PresignTTL:    p.dur("STORAGE_PRESIGN_TTL", 10*time.Minute),
+			S3Bucket:      p.str("S3_BUCKET", ""),
+			S3Region:      p.str("S3_REGION", ""),
+			S3Endpoint:    p.str("S3_ENDPOINT", ""),
+			S3AccessKey:   p.str("S3_ACCESS_KEY", ""),
+			S3SecretKey:   p.str("S3_SECRET_KEY", ""),
+			S3UsePath:     p.bool("S3_USE_PATH_STYLE", true),
 		}
 	}
 
 	if caps.useCache {
 		cfg.Cache = &CacheConfig{
-			Addr:         helpers.GetEnv("REDIS_ADDR", "redis:6379"),
-			Password:     helpers.GetEnv("REDIS_PASSWORD", ""),
-			DB:           helpers.MustInt(helpers.GetEnv("REDIS_DB", "0"), 0),
-			PoolSize:     helpers.MustInt(helpers.GetEnv("REDIS_POOL", "50"), 50),
-			DialTimeout:  5 * time.Second,
-			ReadTimeout:  2 * time.Second,
-			WriteTimeout: 2 * time.Second,
+			Mode:             p.str("REDIS_MODE", "standalone"),
+			Addrs:            p.csv("REDIS_ADDR", "redis:6379"),
+			MasterName:       p.str("REDIS_MASTER_NAME", ""),
+			Username:         p.str("REDIS_USERNAME", ""),
+			Password:         p.str("REDIS_PASSWORD", ""),
+			SentinelPassword: p.str("REDIS_SENTINEL_PASSWORD", ""),
+			DB:               p.int("REDIS_DB", 0),
+			PoolSize:         p.int("REDIS_POOL", 50),
+			MinIdleConns:     p.int("REDIS_MIN_IDLE", 0),
+			DialTimeout:      p.dur("REDIS_DIAL_TIMEOUT", 5*time.Second),
+			ReadTimeout:      p.dur("REDIS_READ_TIMEOUT", 2*time.Second),
+			WriteTimeout:     p.dur("REDIS_WRITE_TIMEOUT", 2*time.Second),
+			TLSEnabled:       p.bool("REDIS_TLS", false),
 		}
+
 	}
 
 	if caps.useSMTP {
 		cfg.SMTP = &SMTPConfig{
-			Host:     helpers.GetEnv("SMTP_HOST", "mailpit"),
-			Port:     helpers.MustInt(helpers.GetEnv("SMTP_PORT", "1025"), 1025),
-			Username: helpers.GetEnv("SMTP_USERNAME", "root"),
-			Password: helpers.GetEnv("SMTP_PASSWORD", "root"),
+			Host:     p.str("SMTP_HOST", "mailpit"),
+			Port:     p.int("SMTP_PORT", 1025),
+			Username: p.str("SMTP_USERNAME", "root"),
+			Password: p.str("SMTP_PASSWORD", "root"),
+
+			PoolSize:    p.int("SMTP_POOL_SIZE", 4),
+			IdleTimeout: p.dur("SMTP_IDLE_TIMEOUT", 30*time.Second),
+			MaxPerConn:  p.int("SMTP_MAX_PER_CONN", 100),
+			RateLimit:   p.float("SMTP_RATE_LIMIT", 0),
+			RateBurst:   p.int("SMTP_RATE_BURST", 10),
 		}
 	}
 
-	if err := cfg.Validate(caps); err != nil {
-		return nil, err
+	if caps.useTracing {
+		cfg.Tracing = &TracingConfig{
+			Endpoint:    p.str("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			Insecure:    p.bool("OTEL_EXPORTER_OTLP_INSECURE", true),
+			SampleRatio: float64(p.int("OTEL_SAMPLE_PERCENT", 100)) / 100,
+		}
 	}
-	return cfg, nil
+
+	if caps.useTLS {
+		cfg.TLS = &TLSConfig{
+			Enabled:        p.bool("TLS_ENABLED", false),
+			CertFile:       p.str("TLS_CERT_FILE", ""),
+			KeyFile:        p.str("TLS_KEY_FILE", ""),
+			CAFile:         p.str("TLS_CA_FILE", ""),
+			AllowedPeers:   p.csv("TLS_ALLOWED_PEERS", ""),
+			ReloadInterval: p.dur("TLS_RELOAD_INTERVAL", time.Minute),
+		}
+	}
+
+	return cfg
 }
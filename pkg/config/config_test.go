@@ -0,0 +1,72 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadParseErrorsAggregated(t *testing.T) {
+	t.Setenv("DB_DSN", "postgres://x")
+	t.Setenv("DB_MAX_OPEN", "many")
+	t.Setenv("DB_QUERY_TIMEOUT", "5")
+	t.Setenv("DB_MIGRATE_ON_START", "maybe")
+
+	_, err := load([]Option{WithDB()})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{
+		`DB_MAX_OPEN="many": expected an integer`,
+		`DB_QUERY_TIMEOUT="5": expected a duration`,
+		`DB_MIGRATE_ON_START="maybe": expected a boolean`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("DB_DSN", "postgres://x")
+
+	cfg, err := load([]Option{WithDB()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.MaxOpen != 20 || cfg.DB.Driver != "postgres" || !cfg.DB.MigrateOnStart {
+		t.Errorf("DB defaults = %+v", cfg.DB)
+	}
+	if cfg.JWT != nil || cfg.Cache != nil {
+		t.Error("sections not requested must stay nil")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		env  map[string]string
+		opts []Option
+		want string
+	}{
+		{"db without dsn", nil, []Option{WithDB()}, "DB_DSN is required"},
+		{"unsupported driver", map[string]string{"DB_DSN": "x", "DB_DRIVER": "oracle"}, []Option{WithDB()}, `unsupported DB_DRIVER "oracle"`},
+		{"prod dev secret", map[string]string{"APP_ENV": "prod"}, []Option{WithJWT()}, "changed from the dev default"},
+		{"prod short secret", map[string]string{"APP_ENV": "prod", "JWT_SECRET": "short"}, []Option{WithJWT()}, "at least 32 bytes"},
+		{"prod cors wildcard", map[string]string{"APP_ENV": "prod"}, []Option{WithCORS()}, "CORS_ALLOW_CREDENTIALS=true"},
+		{"sentinel without master", map[string]string{"REDIS_MODE": "sentinel"}, []Option{WithCache()}, "REDIS_MASTER_NAME is required"},
+		{"cluster with db", map[string]string{"REDIS_MODE": "cluster", "REDIS_DB": "2"}, []Option{WithCache()}, "REDIS_DB must be 0"},
+		{"tls without files", map[string]string{"TLS_ENABLED": "true"}, []Option{WithTLS()}, "TLS_CA_FILE are required"},
+		{"negative drain", map[string]string{"SHUTDOWN_DRAIN_DELAY": "-1s"}, nil, "must not be negative"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			_, err := load(tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+}
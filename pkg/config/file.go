@@ -0,0 +1,243 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// secretKeys можно передать через KEY_FILE — путь к файлу с секретом (Docker/K8s secrets).
+var secretKeys = []string{
+	"DB_DSN",
+	"DB_REPLICA_DSNS",
+	"JWT_SECRET",
+	"REDIS_PASSWORD",
+	"REDIS_SENTINEL_PASSWORD",
+	"SMTP_PASSWORD",
+	"STORAGE_SIGNING_KEY",
+	"S3_ACCESS_KEY",
+	"S3_SECRET_KEY",
+	"UNSUBSCRIBE_SECRET",
+	"MAIL_WEBHOOK_SECRET",
+}
+
+// fileEnv — переменные, выставленные из файлов; при перезагрузке их снимают,
+// чтобы новые значения из файлов не упирались в старые.
+var fileEnv = struct {
+	sync.Mutex
+	keys map[string]struct{}
+}{keys: map[string]struct{}{}}
+
+func setFromFile(key, value string) {
+	fileEnv.Lock()
+	defer fileEnv.Unlock()
+	_ = os.Setenv(key, value)
+	fileEnv.keys[key] = struct{}{}
+}
+
+// resetFileEnv снимает переменные из файлов и возвращает их прежние значения для restoreFileEnv.
+func resetFileEnv() map[string]string {
+	fileEnv.Lock()
+	defer fileEnv.Unlock()
+	saved := make(map[string]string, len(fileEnv.keys))
+	for k := range fileEnv.keys {
+		saved[k] = os.Getenv(k)
+		_ = os.Unsetenv(k)
+	}
+	clear(fileEnv.keys)
+	return saved
+}
+
+// restoreFileEnv возвращает окружение к снимку resetFileEnv после неудачной загрузки.
+func restoreFileEnv(saved map[string]string) {
+	resetFileEnv()
+	fileEnv.Lock()
+	defer fileEnv.Unlock()
+	for k, v := range saved {
+		_ = os.Setenv(k, v)
+		fileEnv.keys[k] = struct{}{}
+	}
+}
+
+// sourceFiles — файлы, из которых собран конфиг: CONFIG_FILE и KEY_FILE секретов.
+func sourceFiles() []string {
+	var out []string
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		out = append(out, path)
+	}
+	for _, key := range secretKeys {
+		if path := os.Getenv(key + "_FILE"); path != "" {
+			out = append(out, path)
+		}
+	}
+	return out
+}
+
+// loadSecretFiles подставляет KEY из KEY_FILE, если сам KEY не задан.
+func loadSecretFiles() error {
+	var errs []error
+	for _, key := range secretKeys {
+		path, ok := os.LookupEnv(key + "_FILE")
+		if !ok || path == "" {
+			continue
+		}
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_FILE: %w", key, err))
+			continue
+		}
+		setFromFile(key, strings.TrimRight(string(b), "\r\n"))
+	}
+	return errors.Join(errs...)
+}
+
+// loadConfigFile читает YAML или TOML из CONFIG_FILE. Вложенные ключи
+// разворачиваются в имена переменных окружения (db: {max_open: 20} → DB_MAX_OPEN),
+// списки — в значения через запятую. Ключ, которого нет с префиксом секции,
+// ищется без него: app: {log_level} → LOG_LEVEL, storage: {s3_bucket} → S3_BUCKET.
+// Неизвестный ключ в секции этого пакета — ошибка; чужие (KAFKA_*, NOTIFY_*)
+// передаются как есть. Заданные переменные окружения не перезаписываются.
+func loadConfigFile() error {
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE: %w", err)
+	}
+
+	raw := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	case ".toml":
+		err = toml.Unmarshal(b, &raw)
+	default:
+		return fmt.Errorf("CONFIG_FILE: unsupported format %q (want .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+
+	flat := map[string]string{}
+	if err := flatten(nil, raw, flat); err != nil {
+		return fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, set := os.LookupEnv(k); !set {
+			setFromFile(k, flat[k])
+		}
+	}
+	return nil
+}
+
+func flatten(path []string, v any, out map[string]string) error {
+	if m, ok := v.(map[string]any); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var errs []error
+		for _, k := range keys {
+			errs = append(errs, flatten(append(path[:len(path):len(path)], k), m[k], out))
+		}
+		return errors.Join(errs...)
+	}
+
+	key, err := envKey(path)
+	if err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case []any:
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		out[key] = strings.Join(parts, ",")
+	case nil:
+		out[key] = ""
+	default:
+		out[key] = fmt.Sprint(t)
+	}
+	return nil
+}
+
+// envKey сопоставляет путь в файле переменной окружения.
+func envKey(path []string) (string, error) {
+	full := joinKey(path)
+	known := knownKeys()
+	if known.has(full) {
+		return full, nil
+	}
+	if !known.owns(full) {
+		return full, nil
+	}
+	if len(path) > 1 {
+		if short := joinKey(path[1:]); known.has(short) {
+			return short, nil
+		}
+	}
+	return "", fmt.Errorf("unknown key %s", strings.Join(path, "."))
+}
+
+func joinKey(path []string) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strings.ToUpper(strings.ReplaceAll(p, "-", "_"))
+	}
+	return strings.Join(parts, "_")
+}
+
+func section(key string) string {
+	s, _, _ := strings.Cut(key, "_")
+	return s
+}
+
+type keySet struct {
+	keys     map[string]struct{}
+	sections map[string]struct{}
+}
+
+func (k keySet) has(key string) bool {
+	_, ok := k.keys[key]
+	return ok
+}
+
+// owns — ключ из секции этого пакета, а не переменная приложения.
+func (k keySet) owns(key string) bool {
+	_, ok := k.sections[section(key)]
+	return ok
+}
+
+// knownKeys — ключи, которые читает Load при всех включённых секциях, и их
+// первые сегменты (DB, REDIS, LOG, ...), плюс имена секций Config.
+var knownKeys = sync.OnceValue(func() keySet {
+	p := parser{seen: map[string]struct{}{}}
+	build(&p, loadCaps{
+		useDB: true, useCORS: true, useJWT: true, useStorage: true,
+		useCache: true, useSMTP: true, useTracing: true, useTLS: true,
+	})
+	ks := keySet{keys: p.seen, sections: map[string]struct{}{"CACHE": {}, "TRACING": {}}}
+	for k := range p.seen {
+		ks.sections[section(k)] = struct{}{}
+	}
+	return ks
+})
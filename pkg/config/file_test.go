@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile кладёт файл во временную директорию теста и возвращает путь.
+func writeFile(t *testing.T, name, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useConfigFile выставляет CONFIG_FILE; переменные из файла снимаются после теста.
+func useConfigFile(t *testing.T, name, body string) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", writeFile(t, name, body))
+	t.Cleanup(func() { resetFileEnv() })
+}
+
+// unsetenv снимает переменную на время теста и возвращает её после.
+func unsetenv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	useConfigFile(t, "app.yaml", `
+app:
+  log_level: debug
+db:
+  dsn: postgres://file
+  max_open: 7
+storage:
+  s3_bucket: media
+cors:
+  allowed_origins: [https://a.example, https://b.example]
+kafka_brokers: kafka:9092
+`)
+	unsetenv(t, "DB_MAX_OPEN")
+
+	cfg, err := load([]Option{WithDB(), WithCORS(), WithStorage()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.App.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want debug", cfg.App.LogLevel)
+	}
+	if cfg.DB.DSN != "postgres://file" || cfg.DB.MaxOpen != 7 {
+		t.Errorf("DB = %q/%d, want postgres://file/7", cfg.DB.DSN, cfg.DB.MaxOpen)
+	}
+	if cfg.Storage.S3Bucket != "media" {
+		t.Errorf("S3Bucket = %q, want media", cfg.Storage.S3Bucket)
+	}
+	if got := strings.Join(cfg.CORS.AllowedOrigins, ","); got != "https://a.example,https://b.example" {
+		t.Errorf("AllowedOrigins = %q", got)
+	}
+	if got := os.Getenv("KAFKA_BROKERS"); got != "kafka:9092" {
+		t.Errorf("KAFKA_BROKERS = %q, want foreign key passed through", got)
+	}
+}
+
+func TestLoadConfigFileTOML(t *testing.T) {
+	useConfigFile(t, "app.toml", `
+[db]
+dsn = "postgres://toml"
+query_timeout = "3s"
+
+[jwt]
+access_ttl = "5m"
+`)
+
+	cfg, err := load([]Option{WithDB(), WithJWT()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.DSN != "postgres://toml" || cfg.DB.QueryTimeout != 3*time.Second {
+		t.Errorf("DB = %q/%v", cfg.DB.DSN, cfg.DB.QueryTimeout)
+	}
+	if cfg.JWT.AccessTTL != 5*time.Minute {
+		t.Errorf("AccessTTL = %v, want 5m", cfg.JWT.AccessTTL)
+	}
+}
+
+func TestLoadConfigFileEnvWins(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	useConfigFile(t, "app.yml", "app:\n  log_level: debug\n")
+
+	cfg, err := load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.App.LogLevel != "warn" {
+		t.Errorf("LogLevel = %q, want env value warn", cfg.App.LogLevel)
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	cases := []struct {
+		name, file, body, want string
+	}{
+		{"unknown key in section", "app.yaml", "db:\n  max_opn: 5\n", "unknown key db.max_opn"},
+		{"unsupported format", "app.json", `{"db":{}}`, "unsupported format"},
+		{"malformed yaml", "app.yaml", "db: [\n", "CONFIG_FILE"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			useConfigFile(t, tc.file, tc.body)
+			_, err := load(nil)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "nope.yaml"))
+		if _, err := load(nil); err == nil {
+			t.Fatal("expected error for missing CONFIG_FILE")
+		}
+	})
+}
+
+func TestEnvKey(t *testing.T) {
+	cases := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"db", "max_open"}, "DB_MAX_OPEN"},
+		{[]string{"app", "log_level"}, "LOG_LEVEL"},
+		{[]string{"storage", "s3_bucket"}, "S3_BUCKET"},
+		{[]string{"redis", "addr"}, "REDIS_ADDR"},
+		{[]string{"notify", "batch-size"}, "NOTIFY_BATCH_SIZE"},
+	}
+	for _, tc := range cases {
+		got, err := envKey(tc.path)
+		if err != nil || got != tc.want {
+			t.Errorf("envKey(%v) = %q, %v; want %q", tc.path, got, err, tc.want)
+		}
+	}
+	if _, err := envKey([]string{"jwt", "secrett"}); err == nil {
+		t.Error("envKey(jwt.secrett): expected error")
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	t.Cleanup(func() { resetFileEnv() })
+
+	t.Run("reads and trims", func(t *testing.T) {
+		t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt", "s3cr3t\n"))
+		unsetenv(t, "JWT_SECRET")
+		t.Cleanup(func() { resetFileEnv() })
+
+		cfg, err := load([]Option{WithJWT()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.JWT.Secret != "s3cr3t" {
+			t.Errorf("Secret = %q, want s3cr3t", cfg.JWT.Secret)
+		}
+	})
+
+	t.Run("env wins", func(t *testing.T) {
+		t.Setenv("JWT_SECRET", "from-env")
+		t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt", "from-file"))
+
+		cfg, err := load([]Option{WithJWT()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.JWT.Secret != "from-env" {
+			t.Errorf("Secret = %q, want from-env", cfg.JWT.Secret)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("DB_DSN_FILE", filepath.Join(t.TempDir(), "nope"))
+		unsetenv(t, "DB_DSN")
+
+		_, err := load([]Option{WithDB()})
+		if err == nil || !strings.Contains(err.Error(), "DB_DSN_FILE") {
+			t.Fatalf("err = %v, want DB_DSN_FILE error", err)
+		}
+	})
+}
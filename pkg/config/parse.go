@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/helpers"
+)
+
+// parser читает переменные окружения и, в отличие от helpers.Must*, копит
+// ошибки разбора вместо тихого отката на значение по умолчанию.
+type parser struct {
+	errs []error
+	// seen — прочитанные ключи; nil, если не нужны
+	seen map[string]struct{}
+}
+
+func (p *parser) str(key, def string) string {
+	p.see(key)
+	return helpers.GetEnv(key, def)
+}
+
+func (p *parser) csv(key, def string) []string {
+	p.see(key)
+	return helpers.Csv(helpers.GetEnv(key, def))
+}
+
+func (p *parser) int(key string, def int) int {
+	v, ok := p.lookup(key)
+	if !ok {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		p.fail(key, v, "an integer")
+		return def
+	}
+	return i
+}
+
+func (p *parser) float(key string, def float64) float64 {
+	v, ok := p.lookup(key)
+	if !ok {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		p.fail(key, v, "a number")
+		return def
+	}
+	return f
+}
+
+func (p *parser) dur(key string, def time.Duration) time.Duration {
+	v, ok := p.lookup(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		p.fail(key, v, "a duration like 15m or 720h")
+		return def
+	}
+	return d
+}
+
+func (p *parser) bool(key string, def bool) bool {
+	v, ok := p.lookup(key)
+	if !ok {
+		return def
+	}
+	switch strings.ToLower(v) {
+	case "1", "true", "yes", "y":
+		return true
+	case "0", "false", "no", "n":
+		return false
+	}
+	p.fail(key, v, "a boolean")
+	return def
+}
+
+// lookup — непустое значение переменной; пустая считается незаданной.
+func (p *parser) lookup(key string) (string, bool) {
+	p.see(key)
+	v := strings.TrimSpace(os.Getenv(key))
+	return v, v != ""
+}
+
+func (p *parser) see(key string) {
+	if p.seen != nil {
+		p.seen[key] = struct{}{}
+	}
+}
+
+func (p *parser) fail(key, val, want string) {
+	p.errs = append(p.errs, fmt.Errorf("%s=%q: expected %s", key, val, want))
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "****"
+
+// Print — подкоманда `config print`: загружает конфиг с opts и печатает его без секретов.
+func Print(w io.Writer, opts ...Option) error {
+	cfg, err := Load(opts...)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, cfg.RedactedString())
+	return err
+}
+
+// RedactedString печатает итоговый конфиг по строке на ключ; секреты замаскированы,
+// из DSN вырезан пароль. Используется командой `config print`.
+func (c *Config) RedactedString() string {
+	var b strings.Builder
+	line := func(key string, val any) { fmt.Fprintf(&b, "%s=%v\n", key, val) }
+
+	line("APP_ENV", c.App.Env)
+	line("APP_HOST", c.App.Host)
+	line("APP_PORT", c.App.Port)
+	line("ADMIN_PORT", c.App.AdminPort)
+	line("LOG_LEVEL", c.App.LogLevel)
+	line("LOG_FORMAT", c.App.LogFormat)
+	line("READ_TIMEOUT", c.App.ReadTimeout)
+	line("WRITE_TIMEOUT", c.App.WriteTimeout)
+	line("IDLE_TIMEOUT", c.App.IdleTimeout)
+	line("SHUTDOWN_TIMEOUT", c.App.ShutdownTimeout)
+	line("SHUTDOWN_DRAIN_DELAY", c.App.DrainDelay)
+
+	if c.DB != nil {
+		replicas := make([]string, 0, len(c.DB.ReplicaDSNs))
+		for _, dsn := range c.DB.ReplicaDSNs {
+			replicas = append(replicas, redactDSN(dsn))
+		}
+		line("DB_DRIVER", c.DB.Driver)
+		line("DB_DSN", redactDSN(c.DB.DSN))
+		line("DB_REPLICA_DSNS", strings.Join(replicas, ","))
+		line("DB_MAX_OPEN", c.DB.MaxOpen)
+		line("DB_MAX_IDLE", c.DB.MaxIdle)
+		line("DB_MAX_IDLE_TIME", c.DB.MaxIdleTime)
+		line("DB_MAX_LIFETIME", c.DB.MaxLifetime)
+		line("DB_QUERY_TIMEOUT", c.DB.QueryTimeout)
+		line("DB_SLOW_THRESHOLD", c.DB.SlowThreshold)
+		line("DB_MIGRATE_ON_START", c.DB.MigrateOnStart)
+	}
+	if c.CORS != nil {
+		line("CORS_ALLOWED_ORIGINS", strings.Join(c.CORS.AllowedOrigins, ","))
+		line("CORS_ALLOWED_METHODS", strings.Join(c.CORS.AllowedMethods, ","))
+		line("CORS_ALLOWED_HEADERS", strings.Join(c.CORS.AllowedHeaders, ","))
+		line("CORS_EXPOSE_HEADERS", strings.Join(c.CORS.ExposedHeaders, ","))
+		line("CORS_ALLOW_CREDENTIALS", c.CORS.AllowCredentials)
+		line("CORS_MAX_AGE", c.CORS.MaxAge)
+	}
+	if c.JWT != nil {
+		line("JWT_SECRET", secret(c.JWT.Secret))
+		line("JWT_ISSUER", c.JWT.Issuer)
+		line("JWT_ACCESS_TTL", c.JWT.AccessTTL)
+		line("JWT_REFRESH_TTL", c.JWT.RefreshTTL)
+	}
+	if c.Storage != nil {
+		line("STORAGE_DRIVER", c.Storage.Driver)
+		line("STORAGE_LOCAL_DIR", c.Storage.LocalDir)
+		line("STORAGE_SIGNING_KEY", secret(c.Storage.SigningKey))
+		line("STORAGE_PUBLIC_BASE_URL", c.Storage.PublicBaseURL)
+		line("STORAGE_PRESIGN_TTL", c.Storage.PresignTTL)
+		line("S3_BUCKET", c.Storage.S3Bucket)
+		line("S3_REGION", c.Storage.S3Region)
+		line("S3_ENDPOINT", c.Storage.S3Endpoint)
+		line("S3_ACCESS_KEY", secret(c.Storage.S3AccessKey))
+		line("S3_SECRET_KEY", secret(c.Storage.S3SecretKey))
+		line("S3_USE_PATH_STYLE", c.Storage.S3UsePath)
+	}
+	if c.Cache != nil {
+		line("REDIS_MODE", c.Cache.Mode)
+		line("REDIS_ADDR", strings.Join(c.Cache.Addrs, ","))
+		line("REDIS_MASTER_NAME", c.Cache.MasterName)
+		line("REDIS_USERNAME", c.Cache.Username)
+		line("REDIS_PASSWORD", secret(c.Cache.Password))
+		line("REDIS_SENTINEL_PASSWORD", secret(c.Cache.SentinelPassword))
+		line("REDIS_DB", c.Cache.DB)
+		line("REDIS_POOL", c.Cache.PoolSize)
+		line("REDIS_MIN_IDLE", c.Cache.MinIdleConns)
+		line("REDIS_DIAL_TIMEOUT", c.Cache.DialTimeout)
+		line("REDIS_READ_TIMEOUT", c.Cache.ReadTimeout)
+		line("REDIS_WRITE_TIMEOUT", c.Cache.WriteTimeout)
+		line("REDIS_TLS", c.Cache.TLSEnabled)
+	}
+	if c.SMTP != nil {
+		line("SMTP_HOST", c.SMTP.Host)
+		line("SMTP_PORT", c.SMTP.Port)
+		line("SMTP_USERNAME", c.SMTP.Username)
+		line("SMTP_PASSWORD", secret(c.SMTP.Password))
+		line("SMTP_POOL_SIZE", c.SMTP.PoolSize)
+		line("SMTP_IDLE_TIMEOUT", c.SMTP.IdleTimeout)
+		line("SMTP_MAX_PER_CONN", c.SMTP.MaxPerConn)
+		line("SMTP_RATE_LIMIT", c.SMTP.RateLimit)
+		line("SMTP_RATE_BURST", c.SMTP.RateBurst)
+	}
+	if c.Tracing != nil {
+		line("OTEL_EXPORTER_OTLP_ENDPOINT", c.Tracing.Endpoint)
+		line("OTEL_EXPORTER_OTLP_INSECURE", c.Tracing.Insecure)
+		line("OTEL_SAMPLE_PERCENT", int(c.Tracing.SampleRatio*100))
+	}
+	if c.TLS != nil {
+		line("TLS_ENABLED", c.TLS.Enabled)
+		line("TLS_CERT_FILE", c.TLS.CertFile)
+		line("TLS_KEY_FILE", c.TLS.KeyFile)
+		line("TLS_CA_FILE", c.TLS.CAFile)
+		line("TLS_ALLOWED_PEERS", strings.Join(c.TLS.AllowedPeers, ","))
+		line("TLS_RELOAD_INTERVAL", c.TLS.ReloadInterval)
+	}
+	return b.String()
+}
+
+func secret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+var dsnPassword = regexp.MustCompile(`(?i)(password=)(\S+)`)
+
+// redactDSN прячет пароль и в URL-форме (postgres://u:p@h/db), и в key=value.
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+		return dsnPassword.ReplaceAllString(u.String(), "${1}"+redacted)
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+)
+
+// ReloadFunc вызывается после успешной перезагрузки; old и cur не меняются,
+// их можно сравнивать и читать без блокировок.
+type ReloadFunc func(old, cur *Config)
+
+// Watcher перечитывает конфиг по SIGHUP и при изменении файлов-источников.
+// На лету меняются только LOG_LEVEL, секция CORS и TTL токенов; остальные
+// секции остаются прежними до рестарта, о расхождении пишется в лог.
+type Watcher struct {
+	opts []Option
+
+	// reload сериализует перезагрузки вместе с вызовом обработчиков
+	reload sync.Mutex
+	cur    *Config
+
+	mu       sync.Mutex
+	handlers []ReloadFunc
+	extra    []string
+	mtimes   map[string]time.Time
+}
+
+// NewWatcher берёт cfg, полученный из Load с теми же opts.
+func NewWatcher(cfg *Config, opts ...Option) *Watcher {
+	w := &Watcher{opts: opts, cur: cfg, mtimes: map[string]time.Time{}}
+	w.snapshot()
+	return w
+}
+
+func (w *Watcher) OnReload(fn ReloadFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, fn)
+}
+
+// WatchFile добавляет файл, изменение которого тоже запускает перезагрузку
+// (например, FLAGS_FILE: обработчики OnReload перечитают его сами).
+func (w *Watcher) WatchFile(path string) {
+	if path == "" {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.extra = append(w.extra, path)
+	if st, err := os.Stat(path); err == nil {
+		w.mtimes[path] = st.ModTime()
+	}
+}
+
+// Reload перечитывает источники и подменяет перезагружаемые секции.
+// При ошибке текущий конфиг и окружение остаются прежними.
+func (w *Watcher) Reload() error {
+	w.reload.Lock()
+	defer w.reload.Unlock()
+
+	saved := resetFileEnv()
+	next, err := load(w.opts)
+	if err != nil {
+		restoreFileEnv(saved)
+		return err
+	}
+
+	old := w.cur
+	merged, restart := merge(old, next)
+	w.cur = merged
+	if len(restart) > 0 {
+		slog.Warn("config changed in sections that need restart", "sections", restart)
+	}
+
+	// обработчики зовутся без w.mu: им можно вызывать OnReload и WatchFile
+	w.mu.Lock()
+	handlers := slices.Clone(w.handlers)
+	w.mu.Unlock()
+	for _, fn := range handlers {
+		fn(old, merged)
+	}
+	return nil
+}
+
+// Run ждёт SIGHUP и раз в interval проверяет mtime файлов-источников. Блокирует до отмены ctx.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			slog.Info("config reload requested", "signal", "SIGHUP")
+		case <-t.C:
+			if !w.changed() {
+				continue
+			}
+			slog.Info("config reload requested", "reason", "file changed")
+		}
+
+		// снимок до перезагрузки: битый файл не будет перечитываться на каждом тике
+		w.snapshot()
+		if err := w.Reload(); err != nil {
+			slog.Error("config reload failed", logx.Err(err))
+			continue
+		}
+		slog.Info("config reloaded")
+	}
+}
+
+func (w *Watcher) files() []string {
+	return append(sourceFiles(), w.extra...)
+}
+
+func (w *Watcher) changed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, path := range w.files() {
+		st, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !st.ModTime().Equal(w.mtimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) snapshot() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, path := range w.files() {
+		if st, err := os.Stat(path); err == nil {
+			w.mtimes[path] = st.ModTime()
+		}
+	}
+}
+
+// merge собирает новый конфиг: перезагружаемые поля берутся из next, остальное из old.
+// Возвращает имена секций, изменения в которых вступят в силу только после рестарта.
+func merge(old, next *Config) (*Config, []string) {
+	out := *old
+	var restart []string
+
+	app := next.App
+	app.LogLevel = old.App.LogLevel
+	if app != old.App {
+		restart = append(restart, "app")
+	}
+	out.App.LogLevel = next.App.LogLevel
+
+	if next.CORS != nil {
+		c := *next.CORS
+		out.CORS = &c
+	}
+
+	if old.JWT != nil && next.JWT != nil {
+		j := *old.JWT
+		j.AccessTTL, j.RefreshTTL = next.JWT.AccessTTL, next.JWT.RefreshTTL
+		out.JWT = &j
+		if j.Secret != next.JWT.Secret || j.Issuer != next.JWT.Issuer {
+			restart = append(restart, "jwt")
+		}
+	}
+
+	for name, pair := range map[string][2]any{
+		"db":      {old.DB, next.DB},
+		"storage": {old.Storage, next.Storage},
+		"cache":   {old.Cache, next.Cache},
+		"smtp":    {old.SMTP, next.SMTP},
+		"tracing": {old.Tracing, next.Tracing},
+		"tls":     {old.TLS, next.TLS},
+	} {
+		if !reflect.DeepEqual(pair[0], pair[1]) {
+			restart = append(restart, name)
+		}
+	}
+	sort.Strings(restart)
+	return &out, restart
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWatcherReload(t *testing.T) {
+	useConfigFile(t, "app.yaml", "app:\n  log_level: info\njwt:\n  access_ttl: 15m\n")
+	opts := []Option{WithJWT(), WithCORS()}
+	cfg, err := load(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(cfg, opts...)
+
+	var calls [][2]*Config
+	w.OnReload(func(old, cur *Config) { calls = append(calls, [2]*Config{old, cur}) })
+
+	path := os.Getenv("CONFIG_FILE")
+	body := "app:\n  log_level: debug\n  port: \"9090\"\njwt:\n  access_ttl: 5m\n  issuer: other\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 1 || calls[0][0] != cfg {
+		t.Fatalf("handler calls = %d, want one with the previous config as old", len(calls))
+	}
+	cur := calls[0][1]
+	if cur.App.LogLevel != "debug" || cur.JWT.AccessTTL != 5*time.Minute {
+		t.Errorf("reloadable fields not applied: level=%q ttl=%v", cur.App.LogLevel, cur.JWT.AccessTTL)
+	}
+	if cur.App.Port != cfg.App.Port || cur.JWT.Issuer != cfg.JWT.Issuer {
+		t.Errorf("restart-only fields changed on the fly: port=%q issuer=%q", cur.App.Port, cur.JWT.Issuer)
+	}
+	if cfg.App.LogLevel != "info" {
+		t.Error("old config must not be mutated")
+	}
+}
+
+func TestWatcherReloadFailureKeepsConfig(t *testing.T) {
+	useConfigFile(t, "app.yaml", "app:\n  log_level: warn\n")
+	cfg, err := load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(cfg)
+	called := false
+	w.OnReload(func(old, cur *Config) { called = true })
+
+	if err := os.WriteFile(os.Getenv("CONFIG_FILE"), []byte("app:\n  log_levle: debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err == nil {
+		t.Fatal("expected reload error")
+	}
+	if called {
+		t.Error("handlers must not run on failed reload")
+	}
+	if w.cur != cfg {
+		t.Error("current config replaced after failed reload")
+	}
+	if got := os.Getenv("LOG_LEVEL"); got != "warn" {
+		t.Errorf("LOG_LEVEL = %q after failed reload, want warn restored", got)
+	}
+}
+
+func TestWatcherChanged(t *testing.T) {
+	useConfigFile(t, "app.yaml", "app:\n  log_level: info\n")
+	extra := writeFile(t, "flags.yaml", "{}\n")
+	w := NewWatcher(&Config{})
+	w.WatchFile(extra)
+
+	if w.changed() {
+		t.Fatal("changed right after snapshot")
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(extra, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !w.changed() {
+		t.Fatal("mtime change of a watched file not detected")
+	}
+	w.snapshot()
+	if w.changed() {
+		t.Fatal("changed after re-snapshot")
+	}
+	if err := os.Chtimes(os.Getenv("CONFIG_FILE"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !w.changed() {
+		t.Fatal("mtime change of CONFIG_FILE not detected")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	old := &Config{
+		App:  AppConfig{LogLevel: "info", Port: "8080"},
+		CORS: &CORSConfig{AllowedOrigins: []string{"*"}},
+		JWT:  &JWTConfig{Secret: "a", Issuer: "app", AccessTTL: time.Minute, RefreshTTL: time.Hour},
+		DB:   &DBConfig{DSN: "x", MaxOpen: 10},
+	}
+
+	t.Run("reloadable only", func(t *testing.T) {
+		next := &Config{
+			App:  AppConfig{LogLevel: "debug", Port: "8080"},
+			CORS: &CORSConfig{AllowedOrigins: []string{"https://a.example"}},
+			JWT:  &JWTConfig{Secret: "a", Issuer: "app", AccessTTL: 2 * time.Minute, RefreshTTL: 2 * time.Hour},
+			DB:   &DBConfig{DSN: "x", MaxOpen: 10},
+		}
+		out, restart := merge(old, next)
+		if len(restart) != 0 {
+			t.Errorf("restart = %v, want none", restart)
+		}
+		if out.App.LogLevel != "debug" || out.CORS.AllowedOrigins[0] != "https://a.example" ||
+			out.JWT.AccessTTL != 2*time.Minute || out.JWT.RefreshTTL != 2*time.Hour {
+			t.Errorf("merged = %+v %+v %+v", out.App, out.CORS, out.JWT)
+		}
+		if out.JWT == old.JWT || out.CORS == next.CORS {
+			t.Error("merged sections must be copies")
+		}
+	})
+
+	t.Run("restart sections", func(t *testing.T) {
+		next := &Config{
+			App:  AppConfig{LogLevel: "info", Port: "9090"},
+			CORS: old.CORS,
+			JWT:  &JWTConfig{Secret: "b", Issuer: "app", AccessTTL: time.Minute, RefreshTTL: time.Hour},
+			DB:   &DBConfig{DSN: "x", MaxOpen: 20},
+		}
+		out, restart := merge(old, next)
+		if want := []string{"app", "db", "jwt"}; !slices.Equal(restart, want) {
+			t.Errorf("restart = %v, want %v", restart, want)
+		}
+		if out.App.Port != "8080" || out.JWT.Secret != "a" || out.DB != old.DB {
+			t.Error("restart-only fields must keep old values")
+		}
+	})
+}
@@ -3,19 +3,28 @@ package db
 import (
 	"context"
 	"database/sql"
+	"log/slog"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 )
 
 type Options struct {
-	DSN         string
+	DSN string
+	// ReplicaDSNs — реплики для чтения; пусто — всё идёт в DSN
+	ReplicaDSNs []string
 	MaxOpen     int
 	MaxIdle     int
 	MaxIdleTime time.Duration
+	MaxLifetime time.Duration
+	// QueryTimeout — таймаут запроса по умолчанию, см. WithQueryTimeout
+	QueryTimeout time.Duration
+	// SlowThreshold — запросы дольше пишутся в лог с уровнем warn; 0 — выключено
+	SlowThreshold time.Duration
 	// Лог-уровень для dev/prod
 	LogLevel logger.LogLevel // logger.Info на dev, logger.Warn на prod
 	// Префиксы/сингуларизация имён таблиц — опционально
@@ -29,8 +38,19 @@ type Conn struct {
 }
 
 func Open(opts Options) (*Conn, error) {
-	gdb, err := gorm.Open(postgres.Open(opts.DSN), &gorm.Config{
-		Logger: logger.Default.LogMode(opts.LogLevel),
+	return open(postgres.Open(opts.DSN), replicas(opts.ReplicaDSNs), opts)
+}
+
+// open — Open с готовыми диалекторами; тесты подставляют sqlmock.
+func open(primary gorm.Dialector, replicas []gorm.Dialector, opts Options) (*Conn, error) {
+	gdb, err := gorm.Open(primary, &gorm.Config{
+		Logger: logger.NewSlogLogger(slog.Default(), logger.Config{
+			LogLevel:                  opts.LogLevel,
+			SlowThreshold:             opts.SlowThreshold,
+			IgnoreRecordNotFoundError: true,
+			// значения параметров (хэши паролей, email) в лог не попадают
+			ParameterizedQueries: true,
+		}),
 		NamingStrategy: schema.NamingStrategy{
 			TablePrefix:   opts.TablePrefix,
 			SingularTable: opts.SingularTable,
@@ -45,14 +65,29 @@ func Open(opts Options) (*Conn, error) {
 		return nil, err
 	}
 
+	// resolver отправляет чтения вне транзакций на реплики, запись — на основной DSN;
+	// настройки пула применяются ко всем соединениям, включая реплики
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	})
 	if opts.MaxOpen > 0 {
-		sdb.SetMaxOpenConns(opts.MaxOpen)
+		resolver.SetMaxOpenConns(opts.MaxOpen)
 	}
 	if opts.MaxIdle > 0 {
-		sdb.SetMaxIdleConns(opts.MaxIdle)
+		resolver.SetMaxIdleConns(opts.MaxIdle)
 	}
 	if opts.MaxIdleTime > 0 {
-		sdb.SetConnMaxIdleTime(opts.MaxIdleTime)
+		resolver.SetConnMaxIdleTime(opts.MaxIdleTime)
+	}
+	if opts.MaxLifetime > 0 {
+		resolver.SetConnMaxLifetime(opts.MaxLifetime)
+	}
+	if err := gdb.Use(resolver); err != nil {
+		return nil, err
+	}
+	if err := gdb.Use(timeoutPlugin{timeout: opts.QueryTimeout}); err != nil {
+		return nil, err
 	}
 
 	// проверочный пинг
@@ -62,6 +97,14 @@ func Open(opts Options) (*Conn, error) {
 	return &Conn{Gorm: gdb, SQL: sdb}, nil
 }
 
+func replicas(dsns []string) []gorm.Dialector {
+	var out []gorm.Dialector
+	for _, dsn := range dsns {
+		out = append(out, postgres.Open(dsn))
+	}
+	return out
+}
+
 func (c *Conn) HealthPing(ctx context.Context) error {
 	return c.SQL.PingContext(ctx)
 }
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type item struct {
+	ID int64
+	N  int
+}
+
+func mockDialector(t *testing.T) (gorm.Dialector, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return postgres.New(postgres.Config{Conn: sqlDB}), mock
+}
+
+func itemRows() *sqlmock.Rows { return sqlmock.NewRows([]string{"id", "n"}).AddRow(1, 1) }
+
+func TestReplicaRouting(t *testing.T) {
+	primaryDial, primary := mockDialector(t)
+	replicaDial, replica := mockDialector(t)
+	c, err := open(primaryDial, []gorm.Dialector{replicaDial}, Options{LogLevel: logger.Silent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	replica.ExpectQuery(`SELECT \* FROM "items"`).WillReturnRows(itemRows())
+	if err := FromContext(ctx, c.Gorm).Find(&[]item{}).Error; err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	primary.ExpectQuery(`SELECT \* FROM "items"`).WillReturnRows(itemRows())
+	if err := FromContext(WithPrimary(ctx), c.Gorm).Find(&[]item{}).Error; err != nil {
+		t.Fatalf("read with WithPrimary: %v", err)
+	}
+
+	primary.ExpectBegin()
+	primary.ExpectExec(`UPDATE "items"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	primary.ExpectCommit()
+	if err := FromContext(ctx, c.Gorm).Model(&item{}).Where("id = ?", 1).Update("n", 2).Error; err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// чтение в транзакции идёт туда же, где транзакция, — в primary
+	primary.ExpectBegin()
+	primary.ExpectQuery(`SELECT \* FROM "items"`).WillReturnRows(itemRows())
+	primary.ExpectCommit()
+	err = NewTxManager(c.Gorm).Do(ctx, func(ctx context.Context) error {
+		return FromContext(ctx, c.Gorm).Find(&[]item{}).Error
+	})
+	if err != nil {
+		t.Fatalf("read in tx: %v", err)
+	}
+
+	for name, m := range map[string]sqlmock.Sqlmock{"primary": primary, "replica": replica} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	dial, mock := mockDialector(t)
+	c, err := open(dial, nil, Options{QueryTimeout: 20 * time.Millisecond, LogLevel: logger.Silent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// на отмену контекста посреди запроса sqlmock отвечает ErrCancelled
+	mock.ExpectQuery(`SELECT`).WillDelayFor(time.Second).WillReturnRows(itemRows())
+	if err := FromContext(ctx, c.Gorm).Find(&[]item{}).Error; !errors.Is(err, sqlmock.ErrCancelled) {
+		t.Fatalf("default timeout: got %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT`).WillDelayFor(50 * time.Millisecond).WillReturnRows(itemRows())
+	if err := FromContext(WithQueryTimeout(ctx, 0), c.Gorm).Find(&[]item{}).Error; err != nil {
+		t.Fatalf("timeout disabled: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT`).WillDelayFor(50 * time.Millisecond).WillReturnRows(itemRows())
+	if err := FromContext(WithQueryTimeout(ctx, time.Second), c.Gorm).Find(&[]item{}).Error; err != nil {
+		t.Fatalf("longer timeout: %v", err)
+	}
+
+	// более ранний дедлайн вызывающего сильнее длинного WithQueryTimeout
+	short, cancel := context.WithTimeout(WithQueryTimeout(ctx, time.Second), 20*time.Millisecond)
+	defer cancel()
+	mock.ExpectQuery(`SELECT`).WillDelayFor(time.Second).WillReturnRows(itemRows())
+	if err := FromContext(short, c.Gorm).Find(&[]item{}).Error; !errors.Is(err, sqlmock.ErrCancelled) {
+		t.Fatalf("caller deadline: got %v", err)
+	}
+}
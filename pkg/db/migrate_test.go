@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func file(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+func TestLoadMigrations(t *testing.T) {
+	ms, err := LoadMigrations(fstest.MapFS{
+		"0002_avatars.up.sql":    file("ALTER 2"),
+		"0002_avatars.down.sql":  file("UNDO 2"),
+		"0001_identity.up.sql":   file("CREATE 1"),
+		"0010_no_down.up.sql":    file("CREATE 10"),
+		"0001_identity.down.sql": file("DROP 1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Migration{
+		{1, "identity", "CREATE 1", "DROP 1"},
+		{2, "avatars", "ALTER 2", "UNDO 2"},
+		{10, "no_down", "CREATE 10", ""},
+	}
+	if len(ms) != len(want) {
+		t.Fatalf("got %+v", ms)
+	}
+	for i := range want {
+		if ms[i] != want[i] {
+			t.Errorf("%d: got %+v, want %+v", i, ms[i], want[i])
+		}
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"no suffix":     {"0001_x.sql": file("")},
+		"no name":       {"0001.up.sql": file("x")},
+		"bad version":   {"v1_x.up.sql": file("x")},
+		"zero version":  {"0000_x.up.sql": file("x")},
+		"name mismatch": {"0001_a.up.sql": file("x"), "0001_b.down.sql": file("x")},
+		"only down":     {"0001_a.down.sql": file("x")},
+	} {
+		if _, err := LoadMigrations(fsys); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+// expectLocked — то, что Migrator делает вокруг любой операции: лок,
+// служебная таблица и список применённых версий.
+func expectLocked(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS " + migrationsTable).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, v := range applied {
+		rows.AddRow(v, time.Now())
+	}
+	mock.ExpectQuery("SELECT version, applied_at FROM " + migrationsTable).WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(migrationLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return &Migrator{db: sqlDB, migrations: []Migration{
+		{1, "a", "UP 1", "DOWN 1"},
+		{2, "b", "UP 2", "DOWN 2"},
+		{3, "c", "UP 3", ""},
+	}}, mock
+}
+
+func TestMigratorUpSkipsApplied(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	expectLocked(mock, 1)
+	for _, mg := range m.migrations[1:] {
+		mock.ExpectBegin()
+		mock.ExpectExec(mg.Up).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO "+migrationsTable).WithArgs(mg.Version, mg.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectUnlock(mock)
+
+	n, err := m.Up(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigratorUpFailureRollsBackAndUnlocks(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	expectLocked(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("UP 3").WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	n, err := m.Up(context.Background())
+	if err == nil || n != 0 || !strings.Contains(err.Error(), "migration 3_c up") {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigratorDown(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	expectLocked(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("DOWN 2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM " + migrationsTable).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	n, err := m.Down(context.Background(), 1)
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigratorDownWithoutScript(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	expectLocked(mock, 1, 2, 3)
+	expectUnlock(mock)
+
+	if _, err := m.Down(context.Background(), 1); err == nil || !strings.Contains(err.Error(), "no down script") {
+		t.Fatalf("got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigratorStatus(t *testing.T) {
+	m, mock := newTestMigrator(t)
+	expectLocked(mock, 2)
+	expectUnlock(mock)
+
+	st, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st) != 3 || st[0].AppliedAt != nil || st[1].AppliedAt == nil || st[2].AppliedAt != nil {
+		t.Fatalf("got %+v", st)
+	}
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type queryTimeoutKey struct{}
+
+// WithQueryTimeout переопределяет таймаут запросов, выполняемых с этим контекстом.
+// d <= 0 — без таймаута (например, для тяжёлых выборок в фоне).
+func WithQueryTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, queryTimeoutKey{}, d)
+}
+
+const timeoutStateKey = "db:timeout_state"
+
+type timeoutState struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// timeoutPlugin ограничивает каждый запрос по времени: берётся таймаут из
+// WithQueryTimeout или дефолтный. Более ранний дедлайн вызывающего остаётся в силе.
+// Row/Rows не трогаем — их читают уже после колбэков.
+type timeoutPlugin struct {
+	timeout time.Duration
+}
+
+func (p timeoutPlugin) Name() string { return "db:timeout" }
+
+func (p timeoutPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	errs := []error{
+		cb.Create().Before("gorm:create").Register("db:timeout_begin", p.begin),
+		cb.Create().After("gorm:create").Register("db:timeout_end", p.end),
+		cb.Query().Before("gorm:query").Register("db:timeout_begin", p.begin),
+		cb.Query().After("gorm:query").Register("db:timeout_end", p.end),
+		cb.Update().Before("gorm:update").Register("db:timeout_begin", p.begin),
+		cb.Update().After("gorm:update").Register("db:timeout_end", p.end),
+		cb.Delete().Before("gorm:delete").Register("db:timeout_begin", p.begin),
+		cb.Delete().After("gorm:delete").Register("db:timeout_end", p.end),
+		cb.Raw().Before("gorm:raw").Register("db:timeout_begin", p.begin),
+		cb.Raw().After("gorm:raw").Register("db:timeout_end", p.end),
+	}
+	return errors.Join(errs...)
+}
+
+func (p timeoutPlugin) begin(db *gorm.DB) {
+	ctx := db.Statement.Context
+	d := p.timeout
+	if v, ok := ctx.Value(queryTimeoutKey{}).(time.Duration); ok {
+		d = v
+	}
+	if d <= 0 {
+		return
+	}
+	tctx, cancel := context.WithTimeout(ctx, d)
+	db.Statement.Context = tctx
+	db.InstanceSet(timeoutStateKey, timeoutState{parent: ctx, cancel: cancel})
+}
+
+func (p timeoutPlugin) end(db *gorm.DB) {
+	// возвращаем исходный контекст: дальше идут хуки и ассоциации со своими запросами
+	if v, ok := db.InstanceGet(timeoutStateKey); ok {
+		st := v.(timeoutState)
+		st.cancel()
+		db.Statement.Context = st.parent
+	}
+}
@@ -1,9 +1,136 @@
 package db
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
 
-func DoTx(db *gorm.DB, fn func(tx *gorm.DB) error) error {
-	return db.Transaction(func(tx *gorm.DB) error {
-		return fn(tx)
-	})
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgUniqueViolation      = "23505"
+
+	defaultTxRetries = 3
+	txRetryBaseDelay = 20 * time.Millisecond
+)
+
+type (
+	txKey      struct{}
+	afterKey   struct{}
+	primaryKey struct{}
+)
+
+// afterCommit — отложенные до коммита действия текущего уровня транзакции.
+type afterCommit struct {
+	fns []func(context.Context)
+}
+
+// FromContext возвращает транзакцию из ctx, если вызов идёт внутри TxManager.Do,
+// иначе — def. Репозитории берут соединение только через него,
+// тогда они сами подхватывают транзакцию вызывающего.
+func FromContext(ctx context.Context, def *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return def.WithContext(ctx).Clauses(dbresolver.Write)
+	}
+	return def.WithContext(ctx)
+}
+
+// WithPrimary направляет чтения через FromContext в primary, а не в реплику:
+// для загрузки в кэш, где отставшая реплика закрепила бы старое значение.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// InTx — выполняется ли ctx внутри транзакции.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// TxManager — unit of work: транзакция живёт в context.Context.
+type TxManager struct {
+	db *gorm.DB
+	// MaxRetries — сколько раз повторить транзакцию при serialization failure/deadlock
+	MaxRetries int
+}
+
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db, MaxRetries: defaultTxRetries}
+}
+
+// AfterCommit откладывает fn до коммита внешней транзакции: письма, события
+// в Kafka и прочее, что нельзя откатить или повторить. При откате (в т.ч.
+// savepoint, в котором fn зарегистрирована) и перед повтором транзакции fn
+// отбрасывается. Вне транзакции fn вызывается сразу.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if ac, ok := ctx.Value(afterKey{}).(*afterCommit); ok {
+		ac.fns = append(ac.fns, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// Do выполняет fn в транзакции. Вложенный вызов открывает savepoint: ошибка fn
+// откатывает только его. Конфликты сериализации и дедлоки ретраятся целиком
+// на внешнем уровне — тело fn может выполниться несколько раз и должно быть
+// идемпотентным: только работа с БД, остальное — через AfterCommit.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		parent, _ := ctx.Value(afterKey{}).(*afterCommit)
+		ac := &afterCommit{}
+		err := tx.WithContext(ctx).Transaction(func(sp *gorm.DB) error {
+			return fn(context.WithValue(context.WithValue(ctx, txKey{}, sp), afterKey{}, ac))
+		})
+		if err == nil && parent != nil {
+			parent.fns = append(parent.fns, ac.fns...)
+		}
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		ac := &afterCommit{}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(context.WithValue(ctx, txKey{}, tx), afterKey{}, ac))
+		}, opts...)
+		if err == nil {
+			for _, f := range ac.fns {
+				f(ctx)
+			}
+			return nil
+		}
+		if !retryable(err) || attempt >= m.MaxRetries {
+			return err
+		}
+
+		delay := txRetryBaseDelay<<attempt + rand.N(txRetryBaseDelay)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// IsUniqueViolation — запись нарушила уникальный индекс (email уже занят и т.п.).
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
 }
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestTx(t *testing.T) (*TxManager, *gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	dial, mock := mockDialector(t)
+	c, err := open(dial, nil, Options{LogLevel: logger.Silent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewTxManager(c.Gorm)
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return m, c.Gorm, mock
+}
+
+func touch(ctx context.Context, g *gorm.DB) error {
+	return FromContext(ctx, g).Exec("UPDATE items SET n = n + 1").Error
+}
+
+func TestAfterCommitOutsideTx(t *testing.T) {
+	ran := false
+	AfterCommit(context.Background(), func(context.Context) { ran = true })
+	if !ran {
+		t.Fatal("not called outside a transaction")
+	}
+}
+
+func TestDoRunsAfterCommitOnlyOnCommit(t *testing.T) {
+	m, g, mock := newTestTx(t)
+	ctx := context.Background()
+
+	var ran []string
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE items").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := m.Do(ctx, func(ctx context.Context) error {
+		if !InTx(ctx) {
+			t.Error("InTx is false inside Do")
+		}
+		AfterCommit(ctx, func(context.Context) { ran = append(ran, "committed") })
+		if len(ran) != 0 {
+			t.Error("AfterCommit ran before commit")
+		}
+		return touch(ctx, g)
+	})
+	if err != nil || len(ran) != 1 {
+		t.Fatalf("commit: err %v, ran %v", err, ran)
+	}
+
+	boom := errors.New("boom")
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = m.Do(ctx, func(ctx context.Context) error {
+		AfterCommit(ctx, func(context.Context) { ran = append(ran, "rolled back") })
+		return boom
+	})
+	if !errors.Is(err, boom) || len(ran) != 1 {
+		t.Fatalf("rollback: err %v, ran %v", err, ran)
+	}
+	if InTx(ctx) {
+		t.Fatal("InTx is true outside Do")
+	}
+}
+
+func TestDoNestedSavepoint(t *testing.T) {
+	m, g, mock := newTestTx(t)
+
+	var ran []string
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE items").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := m.Do(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func(context.Context) { ran = append(ran, "outer") })
+		inner := m.Do(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func(context.Context) { ran = append(ran, "failed savepoint") })
+			return errors.New("conflict")
+		})
+		if inner == nil {
+			t.Error("inner error lost")
+		}
+		return m.Do(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func(context.Context) { ran = append(ran, "savepoint") })
+			return touch(ctx, g)
+		})
+	})
+	if err != nil || fmt.Sprint(ran) != "[outer savepoint]" {
+		t.Fatalf("err %v, ran %v", err, ran)
+	}
+}
+
+func TestDoRetriesSerializationFailure(t *testing.T) {
+	m, g, mock := newTestTx(t)
+
+	serialization := &pgconn.PgError{Code: pgSerializationFailure}
+	for range 2 {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE items").WillReturnError(serialization)
+		mock.ExpectRollback()
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE items").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	attempts, ran := 0, 0
+	err := m.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		AfterCommit(ctx, func(context.Context) { ran++ })
+		return touch(ctx, g)
+	})
+	if err != nil || attempts != 3 || ran != 1 {
+		t.Fatalf("err %v, attempts %d, after-commit calls %d", err, attempts, ran)
+	}
+}
+
+func TestDoGivesUp(t *testing.T) {
+	m, g, mock := newTestTx(t)
+	m.MaxRetries = 1
+
+	deadlock := &pgconn.PgError{Code: pgDeadlockDetected}
+	for range 2 {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE items").WillReturnError(deadlock)
+		mock.ExpectRollback()
+	}
+	if err := m.Do(context.Background(), func(ctx context.Context) error { return touch(ctx, g) }); !errors.As(err, new(*pgconn.PgError)) {
+		t.Fatalf("retries exhausted: got %v", err)
+	}
+
+	// нарушение уникальности не ретраится
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE items").WillReturnError(&pgconn.PgError{Code: pgUniqueViolation})
+	mock.ExpectRollback()
+	err := m.Do(context.Background(), func(ctx context.Context) error { return touch(ctx, g) })
+	if !IsUniqueViolation(err) {
+		t.Fatalf("unique violation: got %v", err)
+	}
+	if IsUniqueViolation(errors.New("duplicate")) {
+		t.Fatal("plain error reported as unique violation")
+	}
+}
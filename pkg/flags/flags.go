@@ -0,0 +1,112 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"sync/atomic"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"gopkg.in/yaml.v3"
+)
+
+// Flag — правило включения фичи.
+//
+//	quests_v2:
+//	  enabled: true
+//	  rollout: 20        # процент пользователей
+//	  users: [01HX...]   # включено всегда, независимо от rollout
+type Flag struct {
+	// Enabled — общий рубильник: выключенный флаг не включается ни для кого
+	Enabled bool     `yaml:"enabled" json:"enabled"`
+	Rollout int      `yaml:"rollout" json:"rollout"`
+	Users   []string `yaml:"users" json:"users"`
+}
+
+// Service хранит набор флагов и подменяет его целиком, читать можно из любых горутин.
+type Service struct {
+	flags atomic.Pointer[map[string]Flag]
+}
+
+func New(flags map[string]Flag) *Service {
+	s := &Service{}
+	s.Replace(flags)
+	return s
+}
+
+// Load читает флаги из YAML-файла вида key: {enabled, rollout, users}.
+func Load(path string) (map[string]Flag, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]Flag{}
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("flags %s: %w", path, err)
+	}
+	for key, f := range out {
+		if f.Rollout < 0 || f.Rollout > 100 {
+			return nil, fmt.Errorf("flags %s: %s: rollout must be within 0..100, got %d", path, key, f.Rollout)
+		}
+	}
+	return out, nil
+}
+
+func (s *Service) Replace(flags map[string]Flag) {
+	if flags == nil {
+		flags = map[string]Flag{}
+	}
+	s.flags.Store(&flags)
+}
+
+// Enabled — включён ли флаг для пользователя. Неизвестный флаг выключен.
+// Процентная раскатка детерминирована: пользователь попадает в один и тот же
+// бакет при каждом вызове, а при увеличении rollout не выпадает из него.
+func (s *Service) Enabled(key, userID string) bool {
+	f, ok := (*s.flags.Load())[key]
+	if !ok {
+		return false
+	}
+	return f.enabledFor(key, userID)
+}
+
+// EnabledCtx берёт пользователя из контекста (logx.WithUserID).
+func (s *Service) EnabledCtx(ctx context.Context, key string) bool {
+	return s.Enabled(key, logx.UserID(ctx))
+}
+
+// Evaluate — все флаги для пользователя; для отдачи клиенту.
+func (s *Service) Evaluate(userID string) map[string]bool {
+	flags := *s.flags.Load()
+	out := make(map[string]bool, len(flags))
+	for key, f := range flags {
+		out[key] = f.enabledFor(key, userID)
+	}
+	return out
+}
+
+func (f Flag) enabledFor(key, userID string) bool {
+	switch {
+	case !f.Enabled:
+		return false
+	case userID != "" && slices.Contains(f.Users, userID):
+		return true
+	case f.Rollout >= 100:
+		return true
+	case f.Rollout <= 0 || userID == "":
+		return false
+	}
+	return bucket(key, userID) < uint32(f.Rollout)
+}
+
+// bucket — номер 0..99; ключ флага в хэше, чтобы разные флаги раскатывались
+// на разные подмножества пользователей.
+func bucket(key, userID string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(userID))
+	return h.Sum32() % 100
+}
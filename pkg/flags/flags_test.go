@@ -0,0 +1,124 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+)
+
+func TestEnabled(t *testing.T) {
+	s := New(map[string]Flag{
+		"off":     {Enabled: false, Rollout: 100, Users: []string{"u1"}},
+		"all":     {Enabled: true, Rollout: 100},
+		"none":    {Enabled: true, Rollout: 0},
+		"allowed": {Enabled: true, Rollout: 0, Users: []string{"u1"}},
+	})
+
+	cases := []struct {
+		key, user string
+		want      bool
+	}{
+		{"off", "u1", false},
+		{"all", "u1", true},
+		{"all", "", true},
+		{"none", "u1", false},
+		{"allowed", "u1", true},
+		{"allowed", "u2", false},
+		{"unknown", "u1", false},
+	}
+	for _, tc := range cases {
+		if got := s.Enabled(tc.key, tc.user); got != tc.want {
+			t.Errorf("Enabled(%q, %q) = %v, want %v", tc.key, tc.user, got, tc.want)
+		}
+	}
+}
+
+func TestRolloutStable(t *testing.T) {
+	s := New(map[string]Flag{"f": {Enabled: true, Rollout: 30}})
+
+	on := map[string]bool{}
+	for i := range 1000 {
+		user := fmt.Sprintf("user-%d", i)
+		on[user] = s.Enabled("f", user)
+		if s.Enabled("f", user) != on[user] {
+			t.Fatalf("%s: result changed between calls", user)
+		}
+	}
+	n := 0
+	for _, v := range on {
+		if v {
+			n++
+		}
+	}
+	if n < 200 || n > 400 {
+		t.Errorf("rollout 30%% enabled %d of 1000 users", n)
+	}
+
+	// при увеличении rollout включённые пользователи остаются включёнными
+	s.Replace(map[string]Flag{"f": {Enabled: true, Rollout: 60}})
+	for user, was := range on {
+		if was && !s.Enabled("f", user) {
+			t.Fatalf("%s dropped out after rollout increase", user)
+		}
+	}
+	if s.Enabled("f", "") {
+		t.Error("anonymous user must not get a partial rollout")
+	}
+}
+
+func TestEnabledCtxAndEvaluate(t *testing.T) {
+	s := New(map[string]Flag{
+		"a": {Enabled: true, Users: []string{"u1"}},
+		"b": {Enabled: false},
+	})
+	ctx := logx.WithUserID(context.Background(), "u1")
+	if !s.EnabledCtx(ctx, "a") {
+		t.Error("EnabledCtx: user from context not used")
+	}
+	got := s.Evaluate("u1")
+	if len(got) != 2 || !got["a"] || got["b"] {
+		t.Errorf("Evaluate = %v", got)
+	}
+
+	s.Replace(nil)
+	if len(s.Evaluate("u1")) != 0 || s.Enabled("a", "u1") {
+		t.Error("Replace(nil) must clear flags")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	write := func(body string) string {
+		path := filepath.Join(t.TempDir(), "flags.yaml")
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	flags, err := Load(write("quests_v2:\n  enabled: true\n  rollout: 20\n  users: [u1]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := flags["quests_v2"]
+	if !f.Enabled || f.Rollout != 20 || len(f.Users) != 1 || f.Users[0] != "u1" {
+		t.Errorf("Load = %+v", f)
+	}
+
+	for _, tc := range []struct{ body, want string }{
+		{"f:\n  rollout: 101\n", "rollout must be within 0..100"},
+		{"f:\n  rollout: -1\n", "rollout must be within 0..100"},
+		{"f: [\n", "flags"},
+	} {
+		if _, err := Load(write(tc.body)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Load(%q) err = %v, want containing %q", tc.body, err, tc.want)
+		}
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load of missing file: expected error")
+	}
+}
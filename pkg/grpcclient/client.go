@@ -0,0 +1,116 @@
+package grpcclient
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+type Options struct {
+	// Addrs — один адрес (host:port или target со схемой, напр. dns:///auth-svc:8081)
+	// либо несколько host:port; во втором случае балансировка round_robin по списку.
+	Addrs []string
+	// Creds — транспорт; nil — без TLS.
+	Creds credentials.TransportCredentials
+	// ServerName — имя для проверки сертификата сервера (SAN), если адреса —
+	// IP или имена не из сертификата. Пусто — хост из адреса.
+	ServerName string
+
+	// DefaultTimeout — дедлайн вызова, если у ctx нет своего.
+	DefaultTimeout time.Duration
+	// Timeouts по полному имени метода ("/pkg.Svc/Method"); 0 — без таймаута
+	// (нужно стримам, которые живут долго).
+	Timeouts map[string]time.Duration
+	// Retry применяется только к Idempotent: полные имена методов или
+	// сервисы целиком ("/pkg.Svc/"). Остальные вызовы не повторяются —
+	// повтор Register мог бы создать пользователя дважды.
+	Retry      RetryPolicy
+	Idempotent []string
+	Breaker    BreakerOptions
+
+	// дописываются после стандартной цепочки
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+	DialOptions        []grpc.DialOption
+}
+
+var schemeSeq atomic.Uint64
+
+// Dial создаёт соединение без ожидания Ready: grpc.NewClient подключается
+// лениво, на первом RPC, и сам переподключается.
+func Dial(opts Options) (*grpc.ClientConn, error) {
+	if len(opts.Addrs) == 0 {
+		return nil, fmt.Errorf("grpcclient: no addresses")
+	}
+
+	creds := opts.Creds
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+
+	sc, err := serviceConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	breaker := NewBreaker(opts.Breaker)
+	do := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(sc),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(append([]grpc.UnaryClientInterceptor{
+			metrics.UnaryClientInterceptor(),
+			logx.UnaryClientInterceptor(),
+			breaker.UnaryClientInterceptor(),
+		}, opts.UnaryInterceptors...)...),
+		grpc.WithChainStreamInterceptor(append([]grpc.StreamClientInterceptor{
+			metrics.StreamClientInterceptor(),
+			logx.StreamClientInterceptor(),
+			breaker.StreamClientInterceptor(),
+		}, opts.StreamInterceptors...)...),
+	}
+
+	target := opts.Addrs[0]
+	if len(opts.Addrs) > 1 {
+		// статический список адресов — через manual resolver со своей схемой
+		r := manual.NewBuilderWithScheme(fmt.Sprintf("static%d", schemeSeq.Add(1)))
+		// authority такого target — вся строка через запятую, поэтому имя для
+		// TLS задаётся каждому адресу отдельно
+		addrs := make([]resolver.Address, 0, len(opts.Addrs))
+		for _, a := range opts.Addrs {
+			addrs = append(addrs, resolver.Address{Addr: a, ServerName: serverName(a, opts.ServerName)})
+		}
+		r.InitialState(resolver.State{Addresses: addrs})
+		do = append(do, grpc.WithResolvers(r))
+		target = r.Scheme() + ":///" + strings.Join(opts.Addrs, ",")
+	} else if !strings.Contains(target, "://") {
+		// dns:/// резолвит все A-записи — round_robin по репликам сервиса
+		target = "dns:///" + target
+	}
+	if opts.ServerName != "" {
+		do = append(do, grpc.WithAuthority(opts.ServerName))
+	}
+
+	return grpc.NewClient(target, append(do, opts.DialOptions...)...)
+}
+
+func serverName(addr, override string) string {
+	if override != "" {
+		return override
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
@@ -0,0 +1,149 @@
+package grpcclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCA выпускает CA и серверный сертификат с заданными DNS SAN.
+func testCA(t *testing.T, dnsNames ...string) (*x509.CertPool, tls.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTpl, caTpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return pool, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startTLSServer поднимает gRPC-сервер с health и считает обработанные вызовы.
+func startTLSServer(t *testing.T, cert tls.Certificate) (string, *atomic.Int64) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls atomic.Int64
+	s := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			calls.Add(1)
+			return h(ctx, req)
+		}),
+	)
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go func() { _ = s.Serve(ln) }()
+	t.Cleanup(s.Stop)
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port, &calls
+}
+
+func checkAll(t *testing.T, opts Options, n int) {
+	t.Helper()
+
+	conn, err := Dial(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	for i := 0; i < n; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		cancel()
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+}
+
+func TestDialMultipleAddrsTLS(t *testing.T) {
+	pool, cert := testCA(t, "localhost")
+	p1, calls1 := startTLSServer(t, cert)
+	p2, calls2 := startTLSServer(t, cert)
+
+	checkAll(t, Options{
+		Addrs:          []string{"localhost:" + p1, "localhost:" + p2},
+		Creds:          credentials.NewTLS(&tls.Config{RootCAs: pool}),
+		DefaultTimeout: 5 * time.Second,
+	}, 10)
+
+	// round_robin: оба бэкенда прошли TLS-handshake и получили вызовы
+	if calls1.Load() == 0 || calls2.Load() == 0 {
+		t.Fatalf("calls not balanced: %d / %d", calls1.Load(), calls2.Load())
+	}
+}
+
+func TestDialServerNameOverride(t *testing.T) {
+	pool, cert := testCA(t, "auth-svc")
+	p1, calls1 := startTLSServer(t, cert)
+	p2, calls2 := startTLSServer(t, cert)
+
+	// IP-адреса не совпадают с SAN сертификата — имя задаётся явно
+	checkAll(t, Options{
+		Addrs:          []string{"127.0.0.1:" + p1, "127.0.0.1:" + p2},
+		Creds:          credentials.NewTLS(&tls.Config{RootCAs: pool}),
+		ServerName:     "auth-svc",
+		DefaultTimeout: 5 * time.Second,
+	}, 10)
+
+	if calls1.Load()+calls2.Load() != 10 {
+		t.Fatalf("unexpected calls: %d / %d", calls1.Load(), calls2.Load())
+	}
+}
@@ -0,0 +1,57 @@
+package grpcclient
+
+import (
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// Registry держит соединения ко всем downstream-сервисам по имени.
+type Registry struct {
+	mu    sync.Mutex
+	names []string
+	conns map[string]*grpc.ClientConn
+}
+
+func NewRegistry() *Registry {
+	return &Registry{conns: make(map[string]*grpc.ClientConn)}
+}
+
+func (r *Registry) Dial(name string, opts Options) (*grpc.ClientConn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.conns[name]; ok {
+		return nil, fmt.Errorf("grpcclient: %q already registered", name)
+	}
+	conn, err := Dial(opts)
+	if err != nil {
+		return nil, fmt.Errorf("grpcclient: dial %s: %w", name, err)
+	}
+	r.names = append(r.names, name)
+	r.conns[name] = conn
+	return conn, nil
+}
+
+// Each обходит соединения в порядке регистрации.
+func (r *Registry) Each(fn func(name string, conn *grpc.ClientConn)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range r.names {
+		fn(n, r.conns[n])
+	}
+}
+
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var firstErr error
+	for _, n := range r.names {
+		if err := r.conns[n].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(r.conns, n)
+	}
+	r.names = nil
+	return firstErr
+}
@@ -0,0 +1,159 @@
+package grpcclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+)
+
+type RetryPolicy struct {
+	// MaxAttempts включая первую попытку; <= 1 — без ретраев.
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	RetryableCodes    []codes.Code
+}
+
+// DefaultRetry — повтор на Unavailable. Это не значит, что сервер запрос не
+// выполнил (соединение могло оборваться после коммита), поэтому политика
+// применяется только к методам из Options.Idempotent.
+var DefaultRetry = RetryPolicy{
+	MaxAttempts:       3,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        time.Second,
+	BackoffMultiplier: 2,
+	RetryableCodes:    []codes.Code{codes.Unavailable},
+}
+
+type methodName struct {
+	Service string `json:"service,omitempty"`
+	Method  string `json:"method,omitempty"`
+}
+
+type retryJSON struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout,omitempty"`
+	RetryPolicy *retryJSON   `json:"retryPolicy,omitempty"`
+}
+
+// serviceConfig собирает JSON service config: round_robin, дефолтный таймаут
+// на все методы ([{}]), retry для идемпотентных методов и переопределения
+// таймаутов по методам. Имена в methodConfig не должны повторяться.
+func serviceConfig(opts Options) (string, error) {
+	retry := retryConfig(opts.Retry)
+
+	mcs := []methodConfig{{
+		Name:    []methodName{{}},
+		Timeout: seconds(opts.DefaultTimeout),
+	}}
+	byName := map[methodName]int{}
+	entry := func(n methodName) *methodConfig {
+		i, ok := byName[n]
+		if !ok {
+			i = len(mcs)
+			byName[n] = i
+			mcs = append(mcs, methodConfig{Name: []methodName{n}, Timeout: seconds(opts.DefaultTimeout)})
+		}
+		return &mcs[i]
+	}
+
+	for _, full := range opts.Idempotent {
+		n, err := parseName(full)
+		if err != nil {
+			return "", err
+		}
+		entry(n).RetryPolicy = retry
+	}
+	for full, d := range opts.Timeouts {
+		n, err := parseName(full)
+		if err != nil {
+			return "", err
+		}
+		mc := entry(n)
+		mc.Timeout = seconds(d)
+		// метод внутри идемпотентного сервиса наследует его retry
+		if mc.RetryPolicy == nil && n.Method != "" {
+			if i, ok := byName[methodName{Service: n.Service}]; ok {
+				mc.RetryPolicy = mcs[i].RetryPolicy
+			}
+		}
+	}
+
+	b, err := json.Marshal(map[string]any{
+		"loadBalancingConfig": []map[string]any{{"round_robin": map[string]any{}}},
+		"methodConfig":        mcs,
+	})
+	return string(b), err
+}
+
+// parseName: "/pkg.Svc/Method" — метод, "/pkg.Svc/" — сервис целиком.
+func parseName(full string) (methodName, error) {
+	if svc, ok := strings.CutSuffix(strings.TrimPrefix(full, "/"), "/"); ok && svc != "" && !strings.Contains(svc, "/") {
+		return methodName{Service: svc}, nil
+	}
+	svc, method, err := splitFullMethod(full)
+	return methodName{Service: svc, Method: method}, err
+}
+
+func retryConfig(p RetryPolicy) *retryJSON {
+	if p.MaxAttempts <= 1 || len(p.RetryableCodes) == 0 {
+		return nil
+	}
+	mult := p.BackoffMultiplier
+	if mult <= 0 {
+		mult = 1
+	}
+	codesJSON := make([]string, 0, len(p.RetryableCodes))
+	for _, c := range p.RetryableCodes {
+		codesJSON = append(codesJSON, codeName(c))
+	}
+	return &retryJSON{
+		MaxAttempts:          p.MaxAttempts,
+		InitialBackoff:       seconds(p.InitialBackoff),
+		MaxBackoff:           seconds(p.MaxBackoff),
+		BackoffMultiplier:    mult,
+		RetryableStatusCodes: codesJSON,
+	}
+}
+
+// seconds — формат длительности service config ("0.100s"); 0 — пусто (нет таймаута).
+func seconds(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+// codeName: codes.DeadlineExceeded -> "DEADLINE_EXCEEDED".
+func codeName(c codes.Code) string {
+	var b strings.Builder
+	for i, r := range c.String() {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func splitFullMethod(full string) (string, string, error) {
+	s := strings.TrimPrefix(full, "/")
+	i := strings.LastIndex(s, "/")
+	if i <= 0 || i == len(s)-1 {
+		return "", "", fmt.Errorf("grpcclient: bad method name %q", full)
+	}
+	return s[:i], s[i+1:], nil
+}
@@ -0,0 +1,125 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hassiimykyta/life-rpg/pkg/jwt"
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Identity — кто вызывает: пользователь (Subject — user id) или сервис
+// (Service — имя сервиса из сервисного токена).
+type Identity struct {
+	Subject string
+	Service string
+}
+
+// AuthFunc проверяет bearer-токен и возвращает вызывающего.
+type AuthFunc func(ctx context.Context, token string) (Identity, error)
+
+// JWTAuth принимает access-токен пользователя или сервисный токен.
+func JWTAuth(m *jwt.Manager) AuthFunc {
+	return func(_ context.Context, token string) (Identity, error) {
+		if c, err := m.VerifyAccess(token); err == nil {
+			return Identity{Subject: c.UserID}, nil
+		}
+		c, err := m.VerifyService(token)
+		if err != nil {
+			return Identity{}, err
+		}
+		return Identity{Service: c.UserID}, nil
+	}
+}
+
+type identityKey struct{}
+
+// Subject — user id из проверенного токена; пусто для публичных методов
+// и вызовов с сервисным токеном.
+func Subject(ctx context.Context) string {
+	id, _ := ctx.Value(identityKey{}).(Identity)
+	return id.Subject
+}
+
+// CallerService — имя сервиса, если вызов пришёл с сервисным токеном.
+func CallerService(ctx context.Context) string {
+	id, _ := ctx.Value(identityKey{}).(Identity)
+	return id.Service
+}
+
+// AuthRules — какие методы доступны без токена и какие только сервисам.
+// Элемент — полное имя метода ("/pkg.Svc/Method") или сервис целиком ("/pkg.Svc/").
+type AuthRules struct {
+	Public  []string
+	Service []string
+}
+
+func AuthUnaryInterceptor(auth AuthFunc, rules AuthRules) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if matchMethod(info.FullMethod, rules.Public) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, auth, matchMethod(info.FullMethod, rules.Service))
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func AuthStreamInterceptor(auth AuthFunc, rules AuthRules) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if matchMethod(info.FullMethod, rules.Public) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), auth, matchMethod(info.FullMethod, rules.Service))
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, auth AuthFunc, serviceOnly bool) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if v := md.Get("authorization"); len(v) > 0 {
+		token, _ = strings.CutPrefix(v[0], "Bearer ")
+	}
+	if token == "" {
+		return ctx, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	id, err := auth(ctx, token)
+	if err != nil {
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if serviceOnly && id.Service == "" {
+		return ctx, status.Error(codes.PermissionDenied, "service token required")
+	}
+	ctx = context.WithValue(ctx, identityKey{}, id)
+	if id.Subject != "" {
+		ctx = logx.WithUserID(ctx, id.Subject)
+	}
+	return ctx, nil
+}
+
+func matchMethod(method string, list []string) bool {
+	for _, p := range list {
+		if method == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(method, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
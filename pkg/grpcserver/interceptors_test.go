@@ -0,0 +1,35 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	authv1 "github.com/hassiimykyta/life-rpg/services/auth/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/auth.v1.AuthService/Register"}
+	for _, tc := range []struct {
+		name string
+		req  any
+		want codes.Code
+	}{
+		{"valid", &authv1.RegisterRequest{Email: "a@b.c", Username: "a", Password: "secret"}, codes.OK},
+		{"short password", &authv1.RegisterRequest{Email: "a@b.c", Username: "a", Password: "123"}, codes.InvalidArgument},
+		{"blank email", &authv1.RegisterRequest{Email: "  ", Username: "a", Password: "secret"}, codes.InvalidArgument},
+		{"login without subject", &authv1.LoginRequest{Password: "secret"}, codes.InvalidArgument},
+		{"no Validate method", "plain", codes.OK},
+	} {
+		called := false
+		_, err := ValidateUnaryInterceptor()(context.Background(), tc.req, info, func(context.Context, any) (any, error) {
+			called = true
+			return nil, nil
+		})
+		if got := status.Code(err); got != tc.want || called != (tc.want == codes.OK) {
+			t.Errorf("%s: got %v, handler called %v", tc.name, got, called)
+		}
+	}
+}
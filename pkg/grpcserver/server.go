@@ -2,20 +2,60 @@ package grpcserver
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"net"
+	"time"
 
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 )
 
 type Server struct {
 	GRPC   *grpc.Server
 	ln     net.Listener
+	errs   chan error
 	closed chan struct{}
 }
 
 type Options struct {
-	Addr   string
+	Addr string
+	// Server — готовый сервер; стандартная цепочка тогда не ставится.
 	Server *grpc.Server
+
+	// TLS — серверный конфиг (mTLS); nil — plaintext.
+	TLS *tls.Config
+
+	// Reflection включает server reflection (grpcurl) — только для dev.
+	Reflection bool
+	// DefaultTimeout ставится unary-вызовам, пришедшим без дедлайна.
+	DefaultTimeout time.Duration
+	// StreamTimeout — предельная длительность стрима без дедлайна клиента;
+	// клиент (SSE в браузере) переподключается. 0 — без ограничения.
+	StreamTimeout time.Duration
+	// Authenticate проверяет токен из metadata authorization; nil — auth выключен.
+	Authenticate AuthFunc
+	// PublicMethods не требуют токена: полное имя метода ("/pkg.Svc/Method")
+	// или сервис целиком ("/pkg.Svc/").
+	PublicMethods []string
+	// ServiceMethods доступны только с сервисным токеном (jwt.SERVICE).
+	ServiceMethods []string
+
+	// дописываются в конец стандартной цепочки
+	UnaryInterceptors  []grpc.UnaryServerInterceptor
+	StreamInterceptors []grpc.StreamServerInterceptor
+	ServerOptions      []grpc.ServerOption
+}
+
+// alwaysPublic — служебные сервисы, которые пробы и grpcurl зовут без токена.
+var alwaysPublic = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
 }
 
 func New(opts Options) (*Server, error) {
@@ -25,18 +65,71 @@ func New(opts Options) (*Server, error) {
 	}
 	s := opts.Server
 	if s == nil {
-		s = grpc.NewServer()
+		s = newServer(opts)
+	}
+	return &Server{GRPC: s, ln: ln, errs: make(chan error, 1), closed: make(chan struct{})}, nil
+}
+
+// newServer собирает цепочку: логирование и метрики снаружи, чтобы видеть
+// итоговый код (в т.ч. Internal после паники), дальше recovery, дедлайн
+// аутентификация и валидация: без токена ответ Unauthenticated, а не
+// InvalidArgument.
+func newServer(opts Options) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{
+		logx.UnaryServerInterceptor(),
+		metrics.UnaryServerInterceptor(),
+		RecoveryUnaryInterceptor(),
+		DeadlineUnaryInterceptor(opts.DefaultTimeout),
 	}
-	return &Server{GRPC: s, ln: ln, closed: make(chan struct{})}, nil
+	stream := []grpc.StreamServerInterceptor{
+		logx.StreamServerInterceptor(),
+		metrics.StreamServerInterceptor(),
+		RecoveryStreamInterceptor(),
+		DeadlineStreamInterceptor(opts.StreamTimeout),
+	}
+	if opts.Authenticate != nil {
+		rules := AuthRules{
+			Public:  append(append([]string(nil), alwaysPublic...), opts.PublicMethods...),
+			Service: opts.ServiceMethods,
+		}
+		unary = append(unary, AuthUnaryInterceptor(opts.Authenticate, rules))
+		stream = append(stream, AuthStreamInterceptor(opts.Authenticate, rules))
+	}
+	unary = append(unary, ValidateUnaryInterceptor())
+	unary = append(unary, opts.UnaryInterceptors...)
+	stream = append(stream, opts.StreamInterceptors...)
+
+	so := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if opts.TLS != nil {
+		so = append(so, grpc.Creds(credentials.NewTLS(opts.TLS)))
+	}
+	s := grpc.NewServer(append(so, opts.ServerOptions...)...)
+	if opts.Reflection {
+		reflection.Register(s)
+	}
+	return s
 }
 
+func (s *Server) Addr() net.Addr { return s.ln.Addr() }
+
 func (s *Server) Start() {
 	go func() {
-		_ = s.GRPC.Serve(s.ln)
+		if err := s.GRPC.Serve(s.ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.errs <- err
+		}
+		close(s.errs)
 		close(s.closed)
 	}()
 }
 
+// Err отдаёт ошибку Serve, если сервер упал сам, а не остановлен Shutdown;
+// закрывается после остановки. Для lifecycle.Runner.Watch.
+func (s *Server) Err() <-chan error { return s.errs }
+
 func (s *Server) Shutdown(ctx context.Context) error {
 	done := make(chan struct{})
 	go func() { s.GRPC.GracefulStop(); close(done) }()
@@ -0,0 +1,244 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type Options struct {
+	// Interval — как часто перепроверять зависимости.
+	Interval time.Duration
+	// Timeout — на одну проверку.
+	Timeout time.Duration
+}
+
+// Checker держит реестр проверок зависимостей и последний результат их прогона.
+// /readyz и grpc.health.v1 отдают этот снимок, а не дёргают БД на каждый probe.
+type Checker struct {
+	opts Options
+	grpc *grpchealth.Server
+
+	mu      sync.RWMutex
+	names   []string
+	checks  map[string]CheckFunc
+	results map[string]string
+	// deps — проверки downstream-сервисов: видны в /healthz/deps, но на
+	// readiness не влияют, иначе падение одного сервиса выводит из
+	// балансировки все реплики вызывающего
+	deps     map[string]bool
+	ready    bool
+	stopping bool
+
+	done chan struct{}
+	once sync.Once
+}
+
+func New(opts Options) *Checker {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	c := &Checker{
+		opts:    opts,
+		grpc:    grpchealth.NewServer(),
+		checks:  make(map[string]CheckFunc),
+		results: make(map[string]string),
+		deps:    make(map[string]bool),
+		done:    make(chan struct{}),
+	}
+	c.grpc.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return c
+}
+
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+	delete(c.deps, name)
+}
+
+// AddDependency — проверка, которая не влияет на readiness (см. Checker.deps).
+func (c *Checker) AddDependency(name string, check CheckFunc) {
+	c.Add(name, check)
+	c.mu.Lock()
+	c.deps[name] = true
+	c.mu.Unlock()
+}
+
+// Start делает первый прогон синхронно и дальше перепроверяет в фоне.
+func (c *Checker) Start(ctx context.Context) {
+	c.run(ctx)
+	go func() {
+		t := time.NewTicker(c.opts.Interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.done:
+				return
+			case <-t.C:
+				c.run(ctx)
+			}
+		}
+	}()
+}
+
+// Shutdown переводит сервис в not ready до остановки серверов: балансировщик
+// успевает снять трафик, пока идёт graceful shutdown.
+func (c *Checker) Shutdown() {
+	c.once.Do(func() {
+		close(c.done)
+		c.mu.Lock()
+		c.stopping = true
+		c.ready = false
+		c.mu.Unlock()
+		c.grpc.Shutdown()
+	})
+}
+
+func (c *Checker) Ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ready
+}
+
+func (c *Checker) run(ctx context.Context) {
+	c.mu.RLock()
+	names := append([]string(nil), c.names...)
+	checks := make(map[string]CheckFunc, len(c.checks))
+	for k, v := range c.checks {
+		checks[k] = v
+	}
+	deps := make(map[string]bool, len(c.deps))
+	for k, v := range c.deps {
+		deps[k] = v
+	}
+	c.mu.RUnlock()
+
+	results := make(map[string]string, len(names))
+	var (
+		wg  sync.WaitGroup
+		rmu sync.Mutex
+	)
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+			defer cancel()
+
+			res := "ok"
+			if err := checks[name](cctx); err != nil {
+				res = err.Error()
+			}
+			rmu.Lock()
+			results[name] = res
+			rmu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	ready := true
+	for name, r := range results {
+		if r != "ok" && !deps[name] {
+			ready = false
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopping {
+		return
+	}
+	c.results = results
+	c.ready = ready
+
+	st := healthpb.HealthCheckResponse_SERVING
+	if !ready {
+		st = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	c.grpc.SetServingStatus("", st)
+}
+
+// RegisterGRPC вешает стандартный grpc.health.v1.Health на сервер.
+func (c *Checker) RegisterGRPC(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, c.grpc)
+}
+
+// Mount добавляет /healthz (liveness: процесс жив), /readyz (readiness: все
+// обязательные проверки ok) и /healthz/deps (состояние downstream-сервисов).
+func (c *Checker) Mount(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+	})
+	mux.HandleFunc("/readyz", c.serveReady)
+	mux.HandleFunc("/healthz/deps", c.serveDeps)
+}
+
+// snapshot — последние результаты обязательных (deps=false) или dependency-проверок.
+func (c *Checker) snapshot(deps bool) map[string]string {
+	out := make(map[string]string, len(c.results))
+	for k, v := range c.results {
+		if c.deps[k] == deps {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (c *Checker) serveDeps(w http.ResponseWriter, r *http.Request) {
+	c.mu.RLock()
+	checks := c.snapshot(true)
+	c.mu.RUnlock()
+
+	body := map[string]any{"status": "ok", "checks": checks}
+	code := http.StatusOK
+	for _, res := range checks {
+		if res != "ok" {
+			body["status"] = "degraded"
+			code = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, code, body)
+}
+
+func (c *Checker) serveReady(w http.ResponseWriter, r *http.Request) {
+	c.mu.RLock()
+	ready, stopping := c.ready, c.stopping
+	checks := c.snapshot(false)
+	c.mu.RUnlock()
+
+	body := map[string]any{"status": "ok", "checks": checks}
+	code := http.StatusOK
+	switch {
+	case stopping:
+		body["status"] = "shutting_down"
+		code = http.StatusServiceUnavailable
+	case !ready:
+		body["status"] = "unavailable"
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, body)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
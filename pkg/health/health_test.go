@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type probe struct {
+	code   int
+	status string
+	checks map[string]string
+}
+
+func get(t *testing.T, mux *http.ServeMux, path string) probe {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var body struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return probe{rec.Code, body.Status, body.Checks}
+}
+
+func grpcStatus(t *testing.T, c *Checker) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := c.grpc.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.GetStatus()
+}
+
+func ok(context.Context) error { return nil }
+
+func TestReadiness(t *testing.T) {
+	c := New(Options{Interval: time.Hour, Timeout: 50 * time.Millisecond})
+	mux := http.NewServeMux()
+	c.Mount(mux)
+
+	if p := get(t, mux, "/readyz"); p.code != http.StatusServiceUnavailable {
+		t.Fatalf("ready before the first run: %+v", p)
+	}
+	if grpcStatus(t, c) != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatal("grpc serving before the first run")
+	}
+
+	var dbErr error
+	c.Add("db", func(context.Context) error { return dbErr })
+	c.AddDependency("auth", func(context.Context) error { return errors.New("connection refused") })
+	c.run(context.Background())
+
+	if p := get(t, mux, "/readyz"); p.code != http.StatusOK || p.checks["db"] != "ok" || p.checks["auth"] != "" {
+		t.Fatalf("failed dependency must not affect readiness: %+v", p)
+	}
+	if p := get(t, mux, "/healthz/deps"); p.code != http.StatusServiceUnavailable || p.status != "degraded" || p.checks["auth"] != "connection refused" {
+		t.Fatalf("deps: %+v", p)
+	}
+	if grpcStatus(t, c) != healthpb.HealthCheckResponse_SERVING {
+		t.Fatal("grpc not serving while ready")
+	}
+
+	dbErr = errors.New("db down")
+	c.run(context.Background())
+	if p := get(t, mux, "/readyz"); p.code != http.StatusServiceUnavailable || p.status != "unavailable" || p.checks["db"] != "db down" {
+		t.Fatalf("failed check: %+v", p)
+	}
+	if p := get(t, mux, "/healthz"); p.code != http.StatusOK {
+		t.Fatalf("liveness depends on checks: %+v", p)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	c := New(Options{Interval: time.Hour, Timeout: 20 * time.Millisecond})
+	c.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	c.run(context.Background())
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("run took %v", d)
+	}
+	if c.Ready() {
+		t.Fatal("ready with a timed out check")
+	}
+}
+
+func TestAddDependencyDemotesCheck(t *testing.T) {
+	c := New(Options{})
+	c.Add("notification", func(context.Context) error { return errors.New("down") })
+	c.AddDependency("notification", func(context.Context) error { return errors.New("down") })
+	c.run(context.Background())
+	if !c.Ready() {
+		t.Fatal("re-added as dependency but still blocks readiness")
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	c := New(Options{Interval: 10 * time.Millisecond})
+	c.Add("db", ok)
+	c.Start(context.Background())
+	if !c.Ready() {
+		t.Fatal("not ready after Start")
+	}
+
+	c.Shutdown()
+	c.Shutdown()
+	time.Sleep(30 * time.Millisecond) // фоновый прогон не должен вернуть ready
+
+	mux := http.NewServeMux()
+	c.Mount(mux)
+	if p := get(t, mux, "/readyz"); p.code != http.StatusServiceUnavailable || p.status != "shutting_down" {
+		t.Fatalf("after Shutdown: %+v", p)
+	}
+	if c.Ready() {
+		t.Fatal("ready after Shutdown")
+	}
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
@@ -21,6 +22,7 @@ func MustInt(s string, def int) int {
 
 	i, err := strconv.Atoi(s)
 	if err != nil {
+		slog.Warn("malformed integer, using default", "value", s, "default", def)
 		return def
 	}
 
@@ -34,6 +36,7 @@ func MustDur(s string, def time.Duration) time.Duration {
 
 	d, err := time.ParseDuration(s)
 	if err != nil {
+		slog.Warn("malformed duration, using default", "value", s, "default", def.String())
 		return def
 	}
 
@@ -46,7 +49,10 @@ func MustBool(s string, def bool) bool {
 		return true
 	case "0", "false", "no", "n":
 		return false
+	case "":
+		return def
 	default:
+		slog.Warn("malformed boolean, using default", "value", s, "default", def)
 		return def
 	}
 }
@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"errors"
 	"net"
 	"net/http"
 	"time"
@@ -10,6 +11,7 @@ import (
 type Server struct {
 	http   *http.Server
 	ln     net.Listener
+	errs   chan error
 	closed chan struct{}
 }
 
@@ -38,17 +40,25 @@ func New(opts Options) (*Server, error) {
 	return &Server{
 		http:   s,
 		ln:     ln,
+		errs:   make(chan error, 1),
 		closed: make(chan struct{}),
 	}, nil
 }
 
 func (s *Server) Start() {
 	go func() {
-		_ = s.http.Serve(s.ln)
+		if err := s.http.Serve(s.ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.errs <- err
+		}
+		close(s.errs)
 		close(s.closed)
 	}()
 }
 
+// Err отдаёт ошибку Serve, если сервер упал сам, а не остановлен Shutdown;
+// закрывается после остановки. Для lifecycle.Runner.Watch.
+func (s *Server) Err() <-chan error { return s.errs }
+
 func (s *Server) Shutdown(ctx context.Context) error {
 	defer func() { <-s.closed }()
 	return s.http.Shutdown(ctx)
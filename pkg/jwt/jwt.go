@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"sync/atomic"
 	"time"
 
 	jwtlib "github.com/golang-jwt/jwt/v5"
@@ -9,6 +10,9 @@ import (
 const (
 	ACCESS  = "access"
 	REFRESH = "refresh"
+	// SERVICE — токен сервиса для вызовов без пользователя (вебхуки и т.п.);
+	// sub — имя сервиса
+	SERVICE = "service"
 )
 
 type Claims struct {
@@ -20,21 +24,29 @@ type Claims struct {
 type Manager struct {
 	secret     []byte
 	issuer     string
-	accessTTL  time.Duration
-	refreshTTL time.Duration
+	accessTTL  atomic.Int64
+	refreshTTL atomic.Int64
 }
 
 func NewManager(secret, issuer string, accessTTL, refreshTTL time.Duration) *Manager {
-	return &Manager{
-		secret:     []byte(secret),
-		issuer:     issuer,
-		accessTTL:  accessTTL,
-		refreshTTL: refreshTTL,
+	m := &Manager{
+		secret: []byte(secret),
+		issuer: issuer,
 	}
+	m.SetTTL(accessTTL, refreshTTL)
+	return m
+}
+
+// SetTTL меняет срок жизни для новых токенов; уже выданные не затрагиваются.
+func (m *Manager) SetTTL(accessTTL, refreshTTL time.Duration) {
+	m.accessTTL.Store(int64(accessTTL))
+	m.refreshTTL.Store(int64(refreshTTL))
 }
 
 func (m *Manager) IssuePair(userID string) (access string, accessExp int64, refresh string, refreshExp int64, err error) {
 	now := time.Now()
+	accessTTL := time.Duration(m.accessTTL.Load())
+	refreshTTL := time.Duration(m.refreshTTL.Load())
 
 	aClaims := Claims{
 		UserID:    userID,
@@ -42,7 +54,7 @@ func (m *Manager) IssuePair(userID string) (access string, accessExp int64, refr
 		RegisteredClaims: jwtlib.RegisteredClaims{
 			Issuer:    m.issuer,
 			Subject:   userID,
-			ExpiresAt: jwtlib.NewNumericDate(now.Add(m.accessTTL)),
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(accessTTL)),
 			IssuedAt:  jwtlib.NewNumericDate(now),
 		},
 	}
@@ -52,7 +64,7 @@ func (m *Manager) IssuePair(userID string) (access string, accessExp int64, refr
 		RegisteredClaims: jwtlib.RegisteredClaims{
 			Issuer:    m.issuer,
 			Subject:   userID,
-			ExpiresAt: jwtlib.NewNumericDate(now.Add(m.refreshTTL)),
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(refreshTTL)),
 			IssuedAt:  jwtlib.NewNumericDate(now),
 		},
 	}
@@ -74,6 +86,25 @@ func (m *Manager) IssuePair(userID string) (access string, accessExp int64, refr
 	return access, aClaims.ExpiresAt.Unix(), refresh, rClaims.ExpiresAt.Unix(), nil
 }
 
+// IssueService выпускает короткоживущий сервисный токен.
+func (m *Manager) IssueService(service string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	return jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, Claims{
+		UserID:    service,
+		TokenType: SERVICE,
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   service,
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwtlib.NewNumericDate(now),
+		},
+	}).SignedString(m.secret)
+}
+
+func (m *Manager) VerifyService(token string) (Claims, error) {
+	return m.verify(token, SERVICE)
+}
+
 func (m *Manager) VerifyAccess(token string) (Claims, error) {
 	return m.verify(token, "access")
 }
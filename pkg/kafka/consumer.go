@@ -2,23 +2,36 @@ package kafka
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"strconv"
+	"time"
 
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Message struct {
-	Key   []byte
-	Value []byte
-	Topic string
+	Key     []byte
+	Value   []byte
+	Topic   string
+	Headers []kafka.Header
 }
 
-type HandlerFunc func(Message) error
+// HandlerFunc получает контекст со спаном обработки, продолжающим трейс продюсера.
+type HandlerFunc func(context.Context, Message) error
 
 type ConsumerConfig struct {
 	Brokers []string
 	Topic   string
 	GroupID string
+	// StartLatest — новая группа читает только сообщения, пришедшие после
+	// подключения (по умолчанию — с начала топика)
+	StartLatest bool
 }
 
 type Consumer struct {
@@ -26,12 +39,17 @@ type Consumer struct {
 }
 
 func NewConsumer(cfg ConsumerConfig) *Consumer {
+	start := kafka.FirstOffset
+	if cfg.StartLatest {
+		start = kafka.LastOffset
+	}
 	r := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  cfg.Brokers,
-		Topic:    cfg.Topic,
-		GroupID:  cfg.GroupID,
-		MinBytes: 1e3,  // 1KB
-		MaxBytes: 10e6, // 10MB
+		Brokers:     cfg.Brokers,
+		Topic:       cfg.Topic,
+		GroupID:     cfg.GroupID,
+		StartOffset: start,
+		MinBytes:    1e3,  // 1KB
+		MaxBytes:    10e6, // 10MB
 	})
 	return &Consumer{r: r}
 }
@@ -44,14 +62,41 @@ func (c *Consumer) Start(ctx context.Context, h HandlerFunc) error {
 		}
 
 		msg := Message{
-			Key:   m.Key,
-			Value: m.Value,
-			Topic: m.Topic,
+			Key:     m.Key,
+			Value:   m.Value,
+			Topic:   m.Topic,
+			Headers: m.Headers,
 		}
 
-		if err := h(msg); err != nil {
-			log.Printf("[kafka] handler error: %v", err)
-		}
+		// Reader.Lag() в группе всегда -1; HighWaterMark — следующий offset партиции
+		metrics.KafkaLag.WithLabelValues(m.Topic, c.r.Config().GroupID, strconv.Itoa(m.Partition)).
+			Set(float64(max(m.HighWaterMark-m.Offset-1, 0)))
+		c.handle(ctx, msg, h)
+	}
+}
+
+func (c *Consumer) handle(ctx context.Context, msg Message, h HandlerFunc) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{&msg.Headers})
+	ctx, span := tracer.Start(ctx, msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.String("messaging.consumer.group.name", c.r.Config().GroupID),
+		),
+	)
+	defer span.End()
+
+	group := c.r.Config().GroupID
+	start := time.Now()
+	err := h(ctx, msg)
+	metrics.KafkaHandleDuration.WithLabelValues(msg.Topic, group).Observe(time.Since(start).Seconds())
+	metrics.KafkaConsumed.WithLabelValues(msg.Topic, group, metrics.Result(err)).Inc()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "kafka handler failed", "topic", msg.Topic, logx.Err(err))
 	}
 }
 
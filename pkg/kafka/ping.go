@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Ping проверяет, что хотя бы один брокер из списка отвечает на metadata-запрос.
+func Ping(ctx context.Context, brokers []string) error {
+	var errs []error
+	for _, addr := range brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if dl, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(dl)
+		}
+		_, err = conn.Brokers()
+		_ = conn.Close()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return errors.New("kafka: no brokers configured")
+	}
+	return errors.Join(errs...)
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"time"
 
+	"github.com/hassiimykyta/life-rpg/pkg/metrics"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Producer struct {
@@ -16,6 +21,8 @@ type ProducerConfig struct {
 	Topic        string
 	RequiredAcks kafka.RequiredAcks
 	Balancer     kafka.Balancer
+	// BatchTimeout — сколько writer копит пачку; по умолчанию 1s из kafka-go
+	BatchTimeout time.Duration
 }
 
 func NewProducer(cfg ProducerConfig) *Producer {
@@ -34,17 +41,37 @@ func NewProducer(cfg ProducerConfig) *Producer {
 			Topic:        cfg.Topic,
 			RequiredAcks: acks,
 			Balancer:     bal,
+			BatchTimeout: cfg.BatchTimeout,
 		},
 	}
 }
 
+// Send пишет сообщение и кладёт trace context в заголовки,
+// чтобы консьюмер продолжил тот же трейс.
 func (p *Producer) Send(ctx context.Context, key, value []byte, headers ...kafka.Header) error {
-	return p.w.WriteMessages(ctx, kafka.Message{
+	ctx, span := tracer.Start(ctx, p.w.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", p.w.Topic),
+		),
+	)
+	defer span.End()
+
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{&headers})
+
+	err := p.w.WriteMessages(ctx, kafka.Message{
 		Key:     key,
 		Value:   value,
 		Time:    time.Now(),
 		Headers: headers,
 	})
+	metrics.KafkaProduced.WithLabelValues(p.w.Topic, metrics.Result(err)).Inc()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
 }
 
 func (p *Producer) Close() error {
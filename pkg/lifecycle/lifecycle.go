@@ -0,0 +1,204 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+)
+
+const defaultShutdownTimeout = 10 * time.Second
+
+// Hook — компонент сервиса. Start вызывается в порядке добавления, Stop — в
+// обратном и только для успешно стартовавших. Любое из полей может быть nil.
+type Hook struct {
+	Name  string
+	Start func(context.Context) error
+	Stop  func(context.Context) error
+}
+
+type Options struct {
+	// ShutdownTimeout — общий бюджет на все Stop; DrainDelay в него не входит
+	ShutdownTimeout time.Duration
+	// DrainDelay — пауза после Drain перед остановкой: балансировщик успевает
+	// увидеть not ready и перестать слать запросы
+	DrainDelay time.Duration
+	// Drain переводит сервис в not ready (обычно health.Checker.Shutdown)
+	Drain func()
+	// Signals — по умолчанию SIGINT и SIGTERM
+	Signals []os.Signal
+}
+
+// Runner запускает хуки, ждёт сигнала или падения фоновой задачи и
+// останавливает всё в обратном порядке.
+type Runner struct {
+	opts  Options
+	hooks []Hook
+	errs  chan error
+}
+
+func New(opts Options) *Runner {
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = defaultShutdownTimeout
+	}
+	if len(opts.Signals) == 0 {
+		opts.Signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	return &Runner{opts: opts, errs: make(chan error, 1)}
+}
+
+func (r *Runner) Append(hooks ...Hook) {
+	r.hooks = append(r.hooks, hooks...)
+}
+
+// Go добавляет фоновую задачу. Она работает до остановки своего хука;
+// ошибка (кроме context.Canceled) запускает остановку всего сервиса
+// и возвращается из Run.
+func (r *Runner) Go(name string, run func(context.Context) error) {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	r.Append(Hook{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			ctx, cancel = context.WithCancel(ctx)
+			done = make(chan struct{})
+			go func() {
+				defer close(done)
+				if err := run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+					r.fail(fmt.Errorf("%s: %w", name, err))
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// Watch останавливает сервис, если из errc пришла ошибка: например, Serve
+// сервера завершился не из-за Shutdown. Закрытие errc без ошибки — штатная остановка.
+func (r *Runner) Watch(name string, errc <-chan error) {
+	r.Append(Hook{
+		Name: name + " watch",
+		Start: func(context.Context) error {
+			go func() {
+				if err, ok := <-errc; ok && err != nil {
+					r.fail(fmt.Errorf("%s: %w", name, err))
+				}
+			}()
+			return nil
+		},
+	})
+}
+
+func (r *Runner) fail(err error) {
+	select {
+	case r.errs <- err:
+	default:
+		// остановка уже запущена первой ошибкой
+		slog.Error("background task failed", logx.Err(err))
+	}
+}
+
+// Run блокируется до сигнала, отмены ctx или ошибки фоновой задачи.
+// Возвращает причину остановки (если это ошибка) вместе с ошибками Stop.
+func (r *Runner) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, r.opts.Signals...)
+	defer signal.Stop(sig)
+
+	started, err := r.start(ctx)
+	if err != nil {
+		return errors.Join(err, r.stop(started, sig))
+	}
+
+	var cause error
+	select {
+	case s := <-sig:
+		slog.Info("shutdown signal", "signal", s.String())
+	case cause = <-r.errs:
+		slog.Error("background task failed, shutting down", logx.Err(cause))
+	case <-ctx.Done():
+		slog.Info("shutdown: context done")
+	}
+
+	r.drain(sig)
+	return errors.Join(cause, r.stop(started, sig))
+}
+
+func (r *Runner) start(ctx context.Context) (int, error) {
+	for i, h := range r.hooks {
+		if h.Start == nil {
+			continue
+		}
+		if err := h.Start(ctx); err != nil {
+			return i, fmt.Errorf("start %s: %w", h.Name, err)
+		}
+	}
+	return len(r.hooks), nil
+}
+
+func (r *Runner) drain(sig <-chan os.Signal) {
+	if r.opts.Drain != nil {
+		r.opts.Drain()
+	}
+	if r.opts.DrainDelay <= 0 {
+		return
+	}
+	t := time.NewTimer(r.opts.DrainDelay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-sig:
+		slog.Warn("second signal, skipping drain delay")
+	}
+}
+
+// stop останавливает первые n хуков в обратном порядке. Повторный сигнал
+// обрывает ожидание: хуки получают уже отменённый контекст.
+func (r *Runner) stop(n int, sig <-chan os.Signal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.ShutdownTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-sig:
+			slog.Warn("second signal, forcing shutdown")
+			cancel()
+		case <-done:
+		}
+	}()
+
+	var errs []error
+	for i := n - 1; i >= 0; i-- {
+		h := r.hooks[i]
+		if h.Stop == nil {
+			continue
+		}
+		if err := h.Stop(ctx); err != nil {
+			slog.Warn("stop failed", "component", h.Name, logx.Err(err))
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
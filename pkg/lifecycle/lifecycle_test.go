@@ -0,0 +1,236 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// recorder пишет порядок вызовов хуков.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, s)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+func (r *recorder) hook(name string, startErr error) Hook {
+	return Hook{
+		Name:  name,
+		Start: func(context.Context) error { r.add("start " + name); return startErr },
+		Stop:  func(context.Context) error { r.add("stop " + name); return nil },
+	}
+}
+
+// newRunner слушает SIGUSR1, чтобы тесты не перехватывали SIGINT/SIGTERM.
+func newRunner(opts Options) *Runner {
+	opts.Signals = []os.Signal{syscall.SIGUSR1}
+	return New(opts)
+}
+
+// run запускает Run в фоне; результат забирается через wait.
+func run(ctx context.Context, r *Runner) <-chan error {
+	out := make(chan error, 1)
+	go func() { out <- r.Run(ctx) }()
+	return out
+}
+
+func wait(t *testing.T, out <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-out:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+		return nil
+	}
+}
+
+func TestStopReverseOrder(t *testing.T) {
+	var rec recorder
+	r := newRunner(Options{})
+	r.Append(rec.hook("db", nil), rec.hook("cache", nil), Hook{Name: "noop"}, rec.hook("server", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start db", "start cache", "start server", "stop server", "stop cache", "stop db"}
+	if got := rec.get(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestStartFailureStopsStarted(t *testing.T) {
+	var rec recorder
+	boom := errors.New("boom")
+	r := newRunner(Options{})
+	r.Append(rec.hook("db", nil), rec.hook("cache", boom), rec.hook("server", nil))
+
+	err := r.Run(context.Background())
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	want := []string{"start db", "start cache", "stop db"}
+	if got := rec.get(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestGoErrorTriggersShutdown(t *testing.T) {
+	var rec recorder
+	boom := errors.New("boom")
+	r := newRunner(Options{})
+	r.Append(rec.hook("db", nil))
+	r.Go("worker", func(ctx context.Context) error { return boom })
+
+	err := wait(t, run(context.Background(), r))
+	if !errors.Is(err, boom) || err.Error() != "worker: boom" {
+		t.Fatalf("err = %v, want worker: boom", err)
+	}
+	if got := rec.get(); !slices.Contains(got, "stop db") {
+		t.Errorf("calls = %v, want db stopped", got)
+	}
+}
+
+func TestGoCanceledIsClean(t *testing.T) {
+	stopped := make(chan struct{})
+	r := newRunner(Options{})
+	r.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := run(ctx, r)
+	cancel()
+	if err := wait(t, out); err != nil {
+		t.Fatalf("err = %v, want nil for canceled task", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("task context not canceled on stop")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		errc := make(chan error, 1)
+		r := newRunner(Options{})
+		r.Watch("http", errc)
+		out := run(context.Background(), r)
+
+		errc <- errors.New("listen failed")
+		if err := wait(t, out); err == nil || err.Error() != "http: listen failed" {
+			t.Fatalf("err = %v, want http: listen failed", err)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		errc := make(chan error)
+		r := newRunner(Options{})
+		r.Watch("http", errc)
+		ctx, cancel := context.WithCancel(context.Background())
+		out := run(ctx, r)
+
+		close(errc)
+		select {
+		case err := <-out:
+			t.Fatalf("Run returned %v after clean close", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+		cancel()
+		if err := wait(t, out); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestSignal(t *testing.T) {
+	var rec recorder
+	r := newRunner(Options{})
+	r.Append(rec.hook("db", nil))
+	started := make(chan struct{})
+	r.Append(Hook{Name: "ready", Start: func(context.Context) error { close(started); return nil }})
+	out := run(context.Background(), r)
+
+	<-started
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	if err := wait(t, out); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.get(); !slices.Contains(got, "stop db") {
+		t.Errorf("calls = %v, want db stopped", got)
+	}
+}
+
+func TestDrainBeforeStop(t *testing.T) {
+	var rec recorder
+	var drained time.Time
+	r := newRunner(Options{
+		DrainDelay: 50 * time.Millisecond,
+		Drain:      func() { drained = time.Now(); rec.add("drain") },
+	})
+	var stopped time.Time
+	r.Append(Hook{Name: "server", Stop: func(context.Context) error {
+		stopped = time.Now()
+		rec.add("stop server")
+		return nil
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.get(); !slices.Equal(got, []string{"drain", "stop server"}) {
+		t.Fatalf("calls = %v", got)
+	}
+	if d := stopped.Sub(drained); d < 50*time.Millisecond {
+		t.Errorf("stop %v after drain, want at least DrainDelay", d)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	var rec recorder
+	r := newRunner(Options{ShutdownTimeout: 50 * time.Millisecond})
+	r.Append(rec.hook("db", nil))
+	r.Append(Hook{Name: "stuck", Stop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err := r.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("shutdown did not respect ShutdownTimeout")
+	}
+	if got := rec.get(); !slices.Contains(got, "stop db") {
+		t.Errorf("calls = %v, want remaining hooks stopped after a timeout", got)
+	}
+}
@@ -0,0 +1,122 @@
+package logx
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Options struct {
+	Service string
+	Env     string
+	Level   string // debug | info | warn | error
+	Format  string // json | text; пусто — json в prod, text иначе
+	Output  io.Writer
+}
+
+// level — уровень дефолтного логгера; меняется на лету через SetLevel.
+var level slog.LevelVar
+
+// Setup собирает логгер и ставит его дефолтным: slog.* и стандартный log.*
+// идут через него же.
+func Setup(opts Options) *slog.Logger {
+	level.Set(ParseLevel(opts.Level))
+	l := newLogger(opts, &level)
+	slog.SetDefault(l)
+	return l
+}
+
+// SetLevel меняет уровень логгера, собранного Setup, без перезапуска.
+func SetLevel(s string) {
+	level.Set(ParseLevel(s))
+}
+
+func New(opts Options) *slog.Logger {
+	return newLogger(opts, ParseLevel(opts.Level))
+}
+
+func newLogger(opts Options, lvl slog.Leveler) *slog.Logger {
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	ho := &slog.HandlerOptions{
+		Level:       lvl,
+		ReplaceAttr: redact,
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = "text"
+		if opts.Env == "prod" {
+			format = "json"
+		}
+	}
+
+	var h slog.Handler
+	if format == "json" {
+		h = slog.NewJSONHandler(out, ho)
+	} else {
+		h = slog.NewTextHandler(out, ho)
+	}
+
+	l := slog.New(contextHandler{h})
+	if opts.Service != "" {
+		l = l.With("service", opts.Service)
+	}
+	return l
+}
+
+func ParseLevel(s string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// contextHandler дописывает к записи request_id, user_id и trace_id/span_id из ctx.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id := RequestID(ctx); id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if id := UserID(ctx); id != "" {
+			r.AddAttrs(slog.String("user_id", id))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Err — атрибут ошибки с единым ключом.
+func Err(err error) slog.Attr {
+	return slog.Any("err", err)
+}
@@ -0,0 +1,37 @@
+package logx
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitive — ключи (без учёта регистра), значения которых в лог не попадают.
+var sensitive = map[string]struct{}{
+	"password":      {},
+	"password_hash": {},
+	"passwordhash":  {},
+	"token":         {},
+	"access_token":  {},
+	"refresh_token": {},
+	"accesstoken":   {},
+	"refreshtoken":  {},
+	"secret":        {},
+	"authorization": {},
+	"cookie":        {},
+	"set-cookie":    {},
+	"api_key":       {},
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if IsSensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+func IsSensitive(key string) bool {
+	_, ok := sensitive[strings.ToLower(key)]
+	return ok
+}
@@ -0,0 +1,12 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// RegisterDBStats экспортирует sql.DBStats пула (go_sql_* с лейблом db_name).
+func RegisterDBStats(name string, db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
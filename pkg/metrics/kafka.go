@@ -0,0 +1,34 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	KafkaProduced = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_messages_produced_total",
+		Help: "Messages written to Kafka by topic and result.",
+	}, []string{"topic", "result"})
+
+	KafkaConsumed = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_messages_consumed_total",
+		Help: "Messages handled by consumers by topic, group and result.",
+	}, []string{"topic", "group", "result"})
+
+	KafkaHandleDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_handle_duration_seconds",
+		Help:    "Consumer handler latency.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"topic", "group"})
+
+	KafkaLag = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "Messages behind the partition end, as of the last read message.",
+	}, []string{"topic", "group", "partition"})
+)
+
+// Result — значение лейбла result.
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/hassiimykyta/life-rpg/pkg/httpserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry — общий реестр процесса; go_* и process_* метрики включены.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Factory — для метрик уровня приложения (бизнес-счётчики).
+func Factory() promauto.Factory { return factory }
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// NewAdminServer — отдельный порт под /metrics и служебные ручки (mount),
+// наружу не публикуется.
+func NewAdminServer(addr string, mount ...func(*http.ServeMux)) (*httpserver.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	for _, m := range mount {
+		m(mux)
+	}
+	return httpserver.New(httpserver.Options{Addr: addr, Handler: mux})
+}
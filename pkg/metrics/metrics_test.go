@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSplitMethod(t *testing.T) {
+	for in, want := range map[string][2]string{
+		"/auth.v1.AuthService/Login": {"auth.v1.AuthService", "Login"},
+		"auth.v1.AuthService/Login":  {"auth.v1.AuthService", "Login"},
+		"Login":                      {"unknown", "Login"},
+	} {
+		if s, m := splitMethod(in); s != want[0] || m != want[1] {
+			t.Errorf("%q: got (%q, %q), want %v", in, s, m, want)
+		}
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	h := HTTPMiddleware(func(r *http.Request) string {
+		if strings.HasPrefix(r.URL.Path, "/users/") {
+			return "/users/{id}"
+		}
+		return ""
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/2" {
+			w.WriteHeader(http.StatusNotFound)
+			w.WriteHeader(http.StatusOK) // повторный WriteHeader не меняет код
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	ok := httpRequests.WithLabelValues(http.MethodGet, "/users/{id}", "200")
+	notFound := httpRequests.WithLabelValues(http.MethodGet, "/users/{id}", "404")
+	unmatched := httpRequests.WithLabelValues(http.MethodGet, "unmatched", "200")
+	before := [3]float64{testutil.ToFloat64(ok), testutil.ToFloat64(notFound), testutil.ToFloat64(unmatched)}
+
+	for _, path := range []string{"/users/1", "/users/2", "/other"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	after := [3]float64{testutil.ToFloat64(ok), testutil.ToFloat64(notFound), testutil.ToFloat64(unmatched)}
+	for i, name := range []string{"200", "404", "unmatched"} {
+		if after[i]-before[i] != 1 {
+			t.Errorf("%s: counted %v requests, want 1", name, after[i]-before[i])
+		}
+	}
+	if v := testutil.ToFloat64(httpInFlight); v != 0 {
+		t.Errorf("in flight after requests: %v", v)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1.Svc/Do"}
+	failed := grpcServerHandled.WithLabelValues("test.v1.Svc", "Do", codes.NotFound.String())
+	before := testutil.ToFloat64(failed)
+
+	_, err := UnaryServerInterceptor()(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "nope")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("error not passed through: %v", err)
+	}
+	if d := testutil.ToFloat64(failed) - before; d != 1 {
+		t.Fatalf("counted %v, want 1", d)
+	}
+}
+
+func TestHandlerExposesRegistry(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), "go_goroutines") {
+		t.Fatal("go collector is not registered")
+	}
+}
@@ -8,19 +8,30 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 )
 
 type Cache struct {
-	Rdb    *redis.Client
+	Rdb    redis.UniversalClient
 	Prefix string
+
+	group singleflight.Group
+}
+
+func NewCache(rdb redis.UniversalClient, prefix string) *Cache {
+	return &Cache{Rdb: rdb, Prefix: prefix}
 }
 
-func (c Cache) key(raw string) string {
+func (c *Cache) key(raw string) string {
 	sum := sha256.Sum256([]byte(raw))
 	return c.Prefix + hex.EncodeToString(sum[:])
 }
 
-func (c Cache) SetEx(ctx context.Context, rawKey string, val any, ttl time.Duration) error {
+func (c *Cache) tagKey(tag string) string {
+	return c.key("tag:" + tag)
+}
+
+func (c *Cache) SetEx(ctx context.Context, rawKey string, val any, ttl time.Duration) error {
 	b, err := json.Marshal(val)
 	if err != nil {
 		return err
@@ -28,7 +39,7 @@ func (c Cache) SetEx(ctx context.Context, rawKey string, val any, ttl time.Durat
 	return c.Rdb.SetEx(ctx, c.key(rawKey), b, ttl).Err()
 }
 
-func (c Cache) GetJSON(ctx context.Context, rawKey string, out any) (bool, error) {
+func (c *Cache) GetJSON(ctx context.Context, rawKey string, out any) (bool, error) {
 	b, err := c.Rdb.Get(ctx, c.key(rawKey)).Bytes()
 	if err == redis.Nil {
 		return false, nil
@@ -39,6 +50,56 @@ func (c Cache) GetJSON(ctx context.Context, rawKey string, out any) (bool, error
 	return true, json.Unmarshal(b, out)
 }
 
-func (c Cache) Del(ctx context.Context, rawKey string) error {
-	return c.Rdb.Del(ctx, c.key(rawKey)).Err()
+func (c *Cache) Del(ctx context.Context, rawKeys ...string) error {
+	// по одному ключу: в кластере DEL нескольких ключей из разных слотов падает
+	_, err := c.Rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, k := range rawKeys {
+			p.Del(ctx, c.key(k))
+		}
+		return nil
+	})
+	return err
+}
+
+// InvalidateTags удаляет все записи, сохранённые с любым из тегов.
+func (c *Cache) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		tk := c.tagKey(tag)
+		members, err := c.Rdb.SMembers(ctx, tk).Result()
+		if err != nil {
+			return err
+		}
+		if _, err := c.Rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+			for _, k := range members {
+				p.Del(ctx, k)
+			}
+			p.Del(ctx, tk)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tag привязывает уже захэшированный ключ к тегам. Множество тега живёт
+// не меньше самой долгой записи в нём: NX ставит TTL новому множеству, GT только продлевает.
+func (c *Cache) tag(ctx context.Context, k string, ttl time.Duration, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := c.Rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, tag := range tags {
+			tk := c.tagKey(tag)
+			p.SAdd(ctx, tk, k)
+			p.ExpireNX(ctx, tk, ttl+time.Second)
+			p.ExpireGT(ctx, tk, ttl+time.Second)
+		}
+		return nil
+	})
+	return err
+}
+
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.Rdb.Ping(ctx).Err()
 }
@@ -0,0 +1,162 @@
+package redisx
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	mrand "math/rand/v2"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotFound возвращает загрузчик GetOrLoad, когда значения нет;
+// при LoadOptions.NegativeTTL > 0 отсутствие тоже кэшируется.
+var ErrNotFound = errors.New("redisx: not found")
+
+const (
+	defaultJitter = 0.1
+
+	// negativeMarker не может быть валидным JSON, так что не путается со значением
+	negativeMarker = "\x00nf"
+	// leasePrefix — метка идущей загрузки; как и negativeMarker, не JSON
+	leasePrefix = "\x00lease:"
+	leaseTTL    = 10 * time.Second
+)
+
+// fillScript пишет значение, только если ключ всё ещё держит lease этой
+// загрузки: Del во время загрузки удаляет lease, и устаревший результат
+// (например, промах до коммита регистрации) в кэш не попадает.
+var fillScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1`)
+
+type LoadOptions[T any] struct {
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	// Jitter — доля TTL, на которую случайно растягивается срок жизни, чтобы
+	// записи, созданные одновременно, не истекали разом. 0 — 10%, < 0 — без разброса.
+	Jitter float64
+	// Tags — теги загруженного значения для InvalidateTags; считаются по самому
+	// значению, потому что при поиске по email id пользователя заранее неизвестен
+	Tags func(v T) []string
+}
+
+// GetOrLoad — cache-aside: отдаёт значение из Redis, а при промахе вызывает load.
+// Одновременные промахи по одному ключу в процессе схлопываются в одну загрузку.
+// Redis здесь необязателен: его ошибки пишутся в лог, и значение грузится напрямую.
+func GetOrLoad[T any](ctx context.Context, c *Cache, rawKey string, opts LoadOptions[T], load func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	k := c.key(rawKey)
+
+	b, err := c.Rdb.Get(ctx, k).Bytes()
+	switch {
+	case err == nil && bytes.HasPrefix(b, []byte(leasePrefix)):
+		// значение грузит другой процесс — грузим сами, но не пишем
+	case err == nil:
+		if string(b) == negativeMarker {
+			return zero, ErrNotFound
+		}
+		var v T
+		if err := json.Unmarshal(b, &v); err == nil {
+			return v, nil
+		}
+		slog.WarnContext(ctx, "cache entry corrupted, reloading", "prefix", c.Prefix)
+	case !errors.Is(err, redis.Nil):
+		slog.WarnContext(ctx, "cache get failed", "prefix", c.Prefix, logx.Err(err))
+	}
+
+	ch := c.group.DoChan(k, func() (any, error) {
+		// загрузка общая для всех ждущих: отмена первого вызова не должна её обрывать
+		lctx := context.WithoutCancel(ctx)
+		lease, leased := c.lease(lctx, k)
+		v, err := load(lctx)
+		if !leased {
+			return v, err
+		}
+		switch {
+		case err == nil:
+			var tags []string
+			if opts.Tags != nil {
+				tags = opts.Tags(v)
+			}
+			b, merr := json.Marshal(v)
+			if merr == nil {
+				merr = c.fill(lctx, k, lease, b, jitter(opts.TTL, opts.Jitter), tags)
+			}
+			if merr != nil {
+				slog.WarnContext(lctx, "cache set failed", "prefix", c.Prefix, logx.Err(merr))
+			}
+		case errors.Is(err, ErrNotFound) && opts.NegativeTTL > 0:
+			if serr := c.fill(lctx, k, lease, []byte(negativeMarker), jitter(opts.NegativeTTL, opts.Jitter), nil); serr != nil {
+				slog.WarnContext(lctx, "cache set failed", "prefix", c.Prefix, logx.Err(serr))
+			}
+		default:
+			_ = c.fill(lctx, k, lease, nil, 0, nil)
+		}
+		return v, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		v, _ := res.Val.(T)
+		return v, nil
+	}
+}
+
+// lease ставит метку загрузки, если ключ пуст. false — ключ занят (значение
+// или чужая загрузка) либо Redis недоступен: результат тогда не пишется.
+func (c *Cache) lease(ctx context.Context, k string) (string, bool) {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	lease := leasePrefix + hex.EncodeToString(buf[:])
+	ok, err := c.Rdb.SetNX(ctx, k, lease, leaseTTL).Result()
+	if err != nil {
+		slog.WarnContext(ctx, "cache lease failed", "prefix", c.Prefix, logx.Err(err))
+		return "", false
+	}
+	return lease, ok
+}
+
+// fill заменяет lease значением b; b == nil — просто снимает lease (ошибка загрузки).
+// Ключ привязывается к тегам до записи: лишний член множества безвреден,
+// а запись без тега пережила бы InvalidateTags.
+func (c *Cache) fill(ctx context.Context, k, lease string, b []byte, ttl time.Duration, tags []string) error {
+	if b == nil {
+		return releaseLease.Run(ctx, c.Rdb, []string{k}, lease).Err()
+	}
+	if err := c.tag(ctx, k, ttl, tags); err != nil {
+		return err
+	}
+	return fillScript.Run(ctx, c.Rdb, []string{k}, lease, b, ttl.Milliseconds()).Err()
+}
+
+var releaseLease = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+func jitter(ttl time.Duration, frac float64) time.Duration {
+	if frac == 0 {
+		frac = defaultJitter
+	}
+	spread := time.Duration(float64(ttl) * frac)
+	if spread <= 0 {
+		return ttl
+	}
+	return ttl + mrand.N(spread)
+}
@@ -0,0 +1,235 @@
+package redisx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type user struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+func TestGetOrLoadCaches(t *testing.T) {
+	_, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	ctx := context.Background()
+	opts := LoadOptions[user]{TTL: time.Minute, Tags: func(u user) []string { return []string{"user:" + u.ID} }}
+
+	var loads atomic.Int32
+	load := func(context.Context) (user, error) {
+		loads.Add(1)
+		return user{ID: "1", Email: "a@example.com"}, nil
+	}
+	for range 2 {
+		u, err := GetOrLoad(ctx, c, "email:a@example.com", opts, load)
+		if err != nil || u.ID != "1" {
+			t.Fatalf("got %+v, %v", u, err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("loaded %d times, want 1", n)
+	}
+
+	if err := c.InvalidateTags(ctx, "user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetOrLoad(ctx, c, "email:a@example.com", opts, load); err != nil || loads.Load() != 2 {
+		t.Fatalf("after InvalidateTags: err %v, loads %d", err, loads.Load())
+	}
+}
+
+func TestGetOrLoadSingleflight(t *testing.T) {
+	_, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (user, error) {
+		loads.Add(1)
+		<-release
+		return user{ID: "1"}, nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u, err := GetOrLoad(context.Background(), c, "k", LoadOptions[user]{TTL: time.Minute}, load)
+			if err == nil && u.ID != "1" {
+				err = errors.New("wrong value")
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Fatalf("loaded %d times, want 1", n)
+	}
+}
+
+func TestGetOrLoadCallerCancelDoesNotAbortLoad(t *testing.T) {
+	mr, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	opts := LoadOptions[user]{TTL: time.Minute}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		_, err := GetOrLoad(ctx, c, "k", opts, func(ctx context.Context) (user, error) {
+			<-release
+			return user{ID: "1"}, ctx.Err()
+		})
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// начавший загрузку уходит по своей отмене, а загрузка доходит до конца
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled caller: got %v", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	u, err := GetOrLoad(context.Background(), c, "k", opts, func(context.Context) (user, error) {
+		t.Error("second load started")
+		return user{}, nil
+	})
+	if err != nil || u.ID != "1" {
+		t.Fatalf("joined caller: got %+v, %v", u, err)
+	}
+	if v, _ := mr.Get(c.key("k")); !strings.Contains(v, `"id":"1"`) {
+		t.Fatalf("cached %q", v)
+	}
+}
+
+func TestGetOrLoadDelDuringLoad(t *testing.T) {
+	mr, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	ctx := context.Background()
+
+	// промах, прочитанный до записи, не должен пережить Del, сделанный после неё
+	_, err := GetOrLoad(ctx, c, "k", LoadOptions[user]{TTL: time.Minute}, func(ctx context.Context) (user, error) {
+		if err := c.Del(ctx, "k"); err != nil {
+			t.Fatal(err)
+		}
+		return user{ID: "stale"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists(c.key("k")) {
+		t.Fatal("stale value written after Del")
+	}
+}
+
+func TestGetOrLoadForeignLease(t *testing.T) {
+	mr, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	foreign := leasePrefix + "other-process"
+	_ = mr.Set(c.key("k"), foreign)
+
+	u, err := GetOrLoad(context.Background(), c, "k", LoadOptions[user]{TTL: time.Minute}, func(context.Context) (user, error) {
+		return user{ID: "1"}, nil
+	})
+	if err != nil || u.ID != "1" {
+		t.Fatalf("got %+v, %v", u, err)
+	}
+	if v, _ := mr.Get(c.key("k")); v != foreign {
+		t.Fatalf("foreign lease overwritten with %q", v)
+	}
+}
+
+func TestGetOrLoadNotFound(t *testing.T) {
+	mr, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	ctx := context.Background()
+
+	var loads atomic.Int32
+	missing := func(context.Context) (user, error) {
+		loads.Add(1)
+		return user{}, ErrNotFound
+	}
+
+	// без NegativeTTL промах не кэшируется и lease снимается
+	for range 2 {
+		if _, err := GetOrLoad(ctx, c, "a", LoadOptions[user]{TTL: time.Minute}, missing); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("got %v", err)
+		}
+	}
+	if loads.Load() != 2 || mr.Exists(c.key("a")) {
+		t.Fatalf("loads %d, key left %v", loads.Load(), mr.Exists(c.key("a")))
+	}
+
+	opts := LoadOptions[user]{TTL: time.Minute, NegativeTTL: time.Second}
+	for range 2 {
+		if _, err := GetOrLoad(ctx, c, "b", opts, missing); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("got %v", err)
+		}
+	}
+	if loads.Load() != 3 {
+		t.Fatalf("negative result not cached: %d loads", loads.Load())
+	}
+	mr.FastForward(2 * time.Second)
+	_, _ = GetOrLoad(ctx, c, "b", opts, missing)
+	if loads.Load() != 4 {
+		t.Fatal("negative entry outlived NegativeTTL")
+	}
+}
+
+func TestGetOrLoadLoadErrorReleasesLease(t *testing.T) {
+	mr, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	boom := errors.New("db down")
+
+	_, err := GetOrLoad(context.Background(), c, "k", LoadOptions[user]{TTL: time.Minute}, func(context.Context) (user, error) {
+		return user{}, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("got %v", err)
+	}
+	if mr.Exists(c.key("k")) {
+		t.Fatal("lease left after failed load")
+	}
+}
+
+func TestGetOrLoadWithoutRedis(t *testing.T) {
+	mr, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	mr.Close()
+
+	u, err := GetOrLoad(context.Background(), c, "k", LoadOptions[user]{TTL: time.Minute}, func(context.Context) (user, error) {
+		return user{ID: "1"}, nil
+	})
+	if err != nil || u.ID != "1" {
+		t.Fatalf("got %+v, %v", u, err)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for range 100 {
+		if d := jitter(time.Second, 0); d < time.Second || d >= 1100*time.Millisecond {
+			t.Fatalf("default jitter out of range: %v", d)
+		}
+	}
+	if d := jitter(time.Second, -1); d != time.Second {
+		t.Fatalf("negative jitter: %v", d)
+	}
+}
@@ -0,0 +1,197 @@
+package redisx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrNotObtained = errors.New("redisx: lock not obtained")
+	ErrLockLost    = errors.New("redisx: lock lost")
+)
+
+const (
+	defaultLockTTL        = 10 * time.Second
+	defaultLockRetryDelay = 50 * time.Millisecond
+)
+
+// refreshScript продлевает ключ, только пока он держит наш токен. Снятие —
+// тот же compare-and-delete, что у lease в GetOrLoad (releaseLease).
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// Locker — распределённая блокировка по схеме Redlock: ключ ставится на каждом
+// независимом инстансе, блокировка взята, если получилось на большинстве и
+// время на захват не съело TTL. С одним клиентом это обычный SET NX PX с токеном.
+// Клиенты должны смотреть в независимые инстансы, а не в реплики одного мастера.
+type Locker struct {
+	clients []redis.UniversalClient
+	prefix  string
+}
+
+func NewLocker(prefix string, clients ...redis.UniversalClient) *Locker {
+	return &Locker{clients: clients, prefix: prefix}
+}
+
+type LockOptions struct {
+	TTL time.Duration
+	// Wait — сколько ждать занятую блокировку; 0 — одна попытка
+	Wait       time.Duration
+	RetryDelay time.Duration
+}
+
+type Lock struct {
+	l     *Locker
+	key   string
+	token string
+	ttl   time.Duration
+}
+
+func (l *Locker) Obtain(ctx context.Context, name string, opts LockOptions) (*Lock, error) {
+	if opts.TTL <= 0 {
+		opts.TTL = defaultLockTTL
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultLockRetryDelay
+	}
+
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, err
+	}
+	lk := &Lock{l: l, key: l.prefix + name, token: hex.EncodeToString(buf[:]), ttl: opts.TTL}
+
+	deadline := time.Now().Add(opts.Wait)
+	for {
+		ok, err := lk.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return lk, nil
+		}
+		if !time.Now().Add(opts.RetryDelay).Before(deadline) {
+			return nil, ErrNotObtained
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(opts.RetryDelay):
+		}
+	}
+}
+
+func (lk *Lock) acquire(ctx context.Context) (bool, error) {
+	start := time.Now()
+	n := lk.each(ctx, func(c redis.UniversalClient) (bool, error) {
+		return c.SetNX(ctx, lk.key, lk.token, lk.ttl).Result()
+	})
+	if n >= lk.quorum() && lk.validity(start) > 0 {
+		return true, nil
+	}
+	// частичный захват нужно снять, иначе остальные будут ждать до истечения TTL
+	lk.release(context.WithoutCancel(ctx))
+	return false, ctx.Err()
+}
+
+// Refresh продлевает аренду ещё на TTL. ErrLockLost — блокировку уже забрали
+// или она истекла на большинстве инстансов.
+func (lk *Lock) Refresh(ctx context.Context) error {
+	start := time.Now()
+	n := lk.each(ctx, func(c redis.UniversalClient) (bool, error) {
+		res, err := refreshScript.Run(ctx, c, []string{lk.key}, lk.token, lk.ttl.Milliseconds()).Int()
+		return res == 1, err
+	})
+	if n < lk.quorum() || lk.validity(start) <= 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (lk *Lock) Release(ctx context.Context) error {
+	if lk.release(ctx) < lk.quorum() {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (lk *Lock) release(ctx context.Context) int {
+	return lk.each(ctx, func(c redis.UniversalClient) (bool, error) {
+		res, err := releaseLease.Run(ctx, c, []string{lk.key}, lk.token).Int()
+		return res == 1, err
+	})
+}
+
+// each выполняет op на всех инстансах параллельно и возвращает число успехов.
+// Ошибка отдельного инстанса — просто неуспех: кворум решает за всех.
+func (lk *Lock) each(ctx context.Context, op func(redis.UniversalClient) (bool, error)) int {
+	res := make(chan bool, len(lk.l.clients))
+	for _, c := range lk.l.clients {
+		go func() {
+			ok, err := op(c)
+			res <- ok && err == nil
+		}()
+	}
+	n := 0
+	for range lk.l.clients {
+		if <-res {
+			n++
+		}
+	}
+	return n
+}
+
+func (lk *Lock) quorum() int { return len(lk.l.clients)/2 + 1 }
+
+// validity — сколько аренды осталось с учётом времени захвата и дрейфа часов.
+func (lk *Lock) validity(start time.Time) time.Duration {
+	drift := lk.ttl/100 + 2*time.Millisecond
+	return lk.ttl - time.Since(start) - drift
+}
+
+// WithLock выполняет fn под блокировкой и продлевает аренду каждые TTL/3.
+// Если продлить не удалось, ctx у fn отменяется, а WithLock возвращает ErrLockLost.
+func (l *Locker) WithLock(ctx context.Context, name string, opts LockOptions, fn func(ctx context.Context) error) error {
+	lk, err := l.Obtain(ctx, name, opts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lk.Release(context.WithoutCancel(ctx)) }()
+
+	fctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		t := time.NewTicker(lk.ttl / 3)
+		defer t.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-fctx.Done():
+				return
+			case <-t.C:
+				if err := lk.Refresh(fctx); err != nil {
+					cancel(ErrLockLost)
+					return
+				}
+			}
+		}
+	}()
+
+	err = fn(fctx)
+	if errors.Is(context.Cause(fctx), ErrLockLost) {
+		return ErrLockLost
+	}
+	return err
+}
@@ -0,0 +1,181 @@
+package redisx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newRedis(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return mr, rdb
+}
+
+func TestLockContention(t *testing.T) {
+	_, rdb := newRedis(t)
+	l := NewLocker("lock:", rdb)
+	ctx := context.Background()
+
+	a, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second}); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("second owner: got %v", err)
+	}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = a.Release(ctx)
+	}()
+	b, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second, Wait: time.Second, RetryDelay: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("waiting for release: %v", err)
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLockExpiry(t *testing.T) {
+	mr, rdb := newRedis(t)
+	l := NewLocker("lock:", rdb)
+	ctx := context.Background()
+
+	a, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(2 * time.Second)
+
+	b, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second})
+	if err != nil {
+		t.Fatalf("after expiry: %v", err)
+	}
+	if err := a.Refresh(ctx); !errors.Is(err, ErrLockLost) {
+		t.Fatalf("refresh of expired lock: got %v", err)
+	}
+	if err := b.Refresh(ctx); err != nil {
+		t.Fatalf("refresh by owner: %v", err)
+	}
+}
+
+func TestLockReleaseByNonOwner(t *testing.T) {
+	mr, rdb := newRedis(t)
+	l := NewLocker("lock:", rdb)
+	ctx := context.Background()
+
+	a, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(2 * time.Second)
+	b, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a опоздал: его Release не должен снять чужую блокировку
+	if err := a.Release(ctx); !errors.Is(err, ErrLockLost) {
+		t.Fatalf("release by previous owner: got %v", err)
+	}
+	if got, _ := mr.Get("lock:job"); got != b.token {
+		t.Fatalf("key holds %q, want owner token", got)
+	}
+	if _, err := l.Obtain(ctx, "job", LockOptions{}); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("lock free after non-owner release: %v", err)
+	}
+}
+
+func TestLockQuorum(t *testing.T) {
+	var (
+		mrs     []*miniredis.Miniredis
+		clients []redis.UniversalClient
+	)
+	for range 3 {
+		mr, rdb := newRedis(t)
+		mrs = append(mrs, mr)
+		clients = append(clients, rdb)
+	}
+	l := NewLocker("lock:", clients...)
+	ctx := context.Background()
+
+	// ключ уже занят на двух инстансах из трёх — кворума нет, и захват
+	// на свободном инстансе снимается
+	_ = mrs[0].Set("lock:job", "other")
+	_ = mrs[1].Set("lock:job", "other")
+	if _, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second}); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("minority: got %v", err)
+	}
+	if mrs[2].Exists("lock:job") {
+		t.Fatal("partial acquire left behind")
+	}
+
+	// один недоступный инстанс кворуму не мешает
+	mrs[0].Del("lock:job")
+	mrs[1].Del("lock:job")
+	mrs[0].Close()
+	lk, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second})
+	if err != nil {
+		t.Fatalf("with one instance down: %v", err)
+	}
+	if err := lk.Refresh(ctx); err != nil {
+		t.Fatalf("refresh with one instance down: %v", err)
+	}
+	if err := lk.Release(ctx); err != nil {
+		t.Fatalf("release with one instance down: %v", err)
+	}
+
+	mrs[1].Close()
+	if _, err := l.Obtain(ctx, "job", LockOptions{TTL: time.Second}); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("with two instances down: got %v", err)
+	}
+}
+
+func TestWithLockCancelsOnLostLease(t *testing.T) {
+	mr, rdb := newRedis(t)
+	l := NewLocker("lock:", rdb)
+
+	err := l.WithLock(context.Background(), "job", LockOptions{TTL: 60 * time.Millisecond}, func(ctx context.Context) error {
+		mr.Del("lock:job")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return errors.New("ctx not cancelled")
+		}
+	})
+	if !errors.Is(err, ErrLockLost) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestWithLockRefreshesAndReleases(t *testing.T) {
+	mr, rdb := newRedis(t)
+	l := NewLocker("lock:", rdb)
+
+	err := l.WithLock(context.Background(), "job", LockOptions{TTL: 60 * time.Millisecond}, func(ctx context.Context) error {
+		// без продлений ключ истёк бы на втором шаге: miniredis двигает время только вручную
+		for range 4 {
+			mr.FastForward(40 * time.Millisecond)
+			time.Sleep(40 * time.Millisecond)
+		}
+		if !mr.Exists("lock:job") {
+			return errors.New("lease not refreshed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists("lock:job") {
+		t.Fatal("lock not released")
+	}
+}
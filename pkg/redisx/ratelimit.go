@@ -0,0 +1,29 @@
+package redisx
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// allowScript — счётчик фиксированного окна: TTL ставится первым INCR окна.
+var allowScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {n, redis.call("PTTL", KEYS[1])}`)
+
+// Allow считает вызов по rawKey и разрешает не больше limit за window.
+// При отказе возвращает, сколько осталось до нового окна.
+func (c *Cache) Allow(ctx context.Context, rawKey string, limit int, window time.Duration) (bool, time.Duration, error) {
+	res, err := allowScript.Run(ctx, c.Rdb, []string{c.key("rl:" + rawKey)}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if res[0] <= int64(limit) {
+		return true, 0, nil
+	}
+	return false, time.Duration(res[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,31 @@
+package redisx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	mr, rdb := newRedis(t)
+	c := NewCache(rdb, "test:")
+	ctx := context.Background()
+
+	for i := range 3 {
+		if ok, _, err := c.Allow(ctx, "login:1.2.3.4", 3, time.Minute); err != nil || !ok {
+			t.Fatalf("call %d: ok %v, err %v", i, ok, err)
+		}
+	}
+	ok, retry, err := c.Allow(ctx, "login:1.2.3.4", 3, time.Minute)
+	if err != nil || ok || retry <= 0 || retry > time.Minute {
+		t.Fatalf("over limit: ok %v, retry %v, err %v", ok, retry, err)
+	}
+	if ok, _, _ := c.Allow(ctx, "login:5.6.7.8", 3, time.Minute); !ok {
+		t.Fatal("limit shared between keys")
+	}
+
+	mr.FastForward(time.Minute)
+	if ok, _, _ := c.Allow(ctx, "login:1.2.3.4", 3, time.Minute); !ok {
+		t.Fatal("window did not reset")
+	}
+}
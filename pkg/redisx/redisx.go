@@ -9,34 +9,85 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
 type Config struct {
-	Addr         string
-	Password     string
-	DB           int
-	DialTimeout  time.Duration
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	PoolSize     int
-	MinIdleConns int
-	TLSEnabled   bool
+	Mode string
+	// Addrs: standalone — один адрес, sentinel — адреса sentinel'ей, cluster — seed-ноды
+	Addrs            []string
+	MasterName       string
+	Username         string
+	Password         string
+	SentinelPassword string
+	DB               int
+	DialTimeout      time.Duration
+	ReadTimeout      time.Duration
+	WriteTimeout     time.Duration
+	PoolSize         int
+	MinIdleConns     int
+	TLSEnabled       bool
 }
 
-func New(ctx context.Context, cfg Config) (*redis.Client, func() error, error) {
-	opts := &redis.Options{
-		Addr:         cfg.Addr,
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		DialTimeout:  cfg.DialTimeout,
-		ReadTimeout:  cfg.ReadTimeout,
-		WriteTimeout: cfg.WriteTimeout,
-		PoolSize:     cfg.PoolSize,
-		MinIdleConns: cfg.MinIdleConns,
+func New(ctx context.Context, cfg Config) (redis.UniversalClient, func() error, error) {
+	if len(cfg.Addrs) == 0 {
+		return nil, nil, fmt.Errorf("redis: no addresses")
 	}
+
+	var tlsCfg *tls.Config
 	if cfg.TLSEnabled {
-		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
 	}
 
-	rdb := redis.NewClient(opts)
+	var rdb redis.UniversalClient
+	switch cfg.Mode {
+	case "", ModeStandalone:
+		rdb = redis.NewClient(&redis.Options{
+			Addr:         cfg.Addrs[0],
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			TLSConfig:    tlsCfg,
+		})
+	case ModeSentinel:
+		rdb = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+			PoolSize:         cfg.PoolSize,
+			MinIdleConns:     cfg.MinIdleConns,
+			TLSConfig:        tlsCfg,
+		})
+	case ModeCluster:
+		// в кластере нет SELECT, DB всегда 0
+		rdb = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			TLSConfig:    tlsCfg,
+		})
+	default:
+		return nil, nil, fmt.Errorf("redis: unknown mode %q", cfg.Mode)
+	}
 
 	ct, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
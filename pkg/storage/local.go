@@ -0,0 +1,280 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalPrefix — путь, под которым Local.Handler отдаёт и принимает объекты.
+const LocalPrefix = "/media/"
+
+type LocalOptions struct {
+	Dir string
+	// PublicBaseURL — адрес сервиса, где смонтирован Handler
+	PublicBaseURL string
+	SigningKey    string
+	// PublicPrefixes — ключи, которые читаются без подписи (ссылки из PublicURL),
+	// как у бакета с anonymous download на префикс
+	PublicPrefixes []string
+}
+
+// Local хранит объекты в файловой системе; для разработки и тестов.
+// Presigned-ссылки обслуживает Handler: загрузка только по подписи,
+// чтение — по подписи или из PublicPrefixes.
+type Local struct {
+	dir     string
+	baseURL string
+	key     []byte
+	public  []string
+}
+
+func NewLocal(o LocalOptions) (*Local, error) {
+	if o.Dir == "" {
+		return nil, errors.New("storage: local dir is required")
+	}
+	if o.SigningKey == "" {
+		return nil, errors.New("storage: signing key is required for local driver")
+	}
+	if err := os.MkdirAll(o.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+	return &Local{dir: o.Dir, baseURL: o.PublicBaseURL, key: []byte(o.SigningKey), public: o.PublicPrefixes}, nil
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, meta Meta) (Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+	p := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return Object{}, err
+	}
+
+	// пишем во временный файл рядом и переименовываем: читатели не видят недописанный объект
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Object{}, err
+	}
+	if meta.Size >= 0 && n != meta.Size {
+		return Object{}, fmt.Errorf("storage: size mismatch: got %d bytes, expected %d", n, meta.Size)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return Object{}, err
+	}
+	return l.Stat(ctx, key)
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	obj, err := l.Stat(ctx, key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	f, err := os.Open(l.path(obj.Key))
+	if err != nil {
+		return nil, Object{}, mapFSErr(err)
+	}
+	return f, obj, nil
+}
+
+func (l *Local) Stat(_ context.Context, key string) (Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+	p := l.path(key)
+	st, err := os.Stat(p)
+	if err != nil {
+		return Object{}, mapFSErr(err)
+	}
+	if st.IsDir() {
+		return Object{}, ErrNotFound
+	}
+	ct, err := detectFileType(p)
+	if err != nil {
+		return Object{}, err
+	}
+	return Object{
+		Key:         key,
+		ContentType: ct,
+		Size:        st.Size(),
+		ETag:        fmt.Sprintf("%x-%x", st.ModTime().UnixNano(), st.Size()),
+		ModTime:     st.ModTime(),
+	}, nil
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(l.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) PresignPut(_ context.Context, key string, meta Meta, ttl time.Duration) (Presigned, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return Presigned{}, err
+	}
+	exp := time.Now().Add(ttl).Truncate(time.Second)
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(exp.Unix(), 10))
+	q.Set("signature", l.sign(http.MethodPut, key, exp.Unix(), meta))
+	return Presigned{
+		Method: http.MethodPut,
+		URL:    joinURL(l.baseURL, strings.TrimPrefix(LocalPrefix, "/")+key) + "?" + q.Encode(),
+		Headers: map[string]string{
+			"Content-Type":   meta.ContentType,
+			"Content-Length": strconv.FormatInt(meta.Size, 10),
+		},
+		ExpiresAt: exp,
+	}, nil
+}
+
+func (l *Local) PresignGet(_ context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	exp := time.Now().Add(ttl).Unix()
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(exp, 10))
+	q.Set("signature", l.sign(http.MethodGet, key, exp, Meta{}))
+	return l.PublicURL(key) + "?" + q.Encode(), nil
+}
+
+func (l *Local) PublicURL(key string) string {
+	return joinURL(l.baseURL, strings.TrimPrefix(LocalPrefix, "/")+key)
+}
+
+// Handler обслуживает LocalPrefix: GET отдаёт файл по ссылке из PresignGet
+// (без подписи — только из PublicPrefixes), PUT принимает загрузку по ссылке из PresignPut.
+func (l *Local) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, err := cleanKey(strings.TrimPrefix(r.URL.Path, LocalPrefix))
+		if err != nil {
+			http.Error(w, "invalid key", http.StatusBadRequest)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			ok := l.isPublic(key)
+			if r.URL.Query().Has("signature") {
+				ok = l.verify(r, http.MethodGet, key, Meta{})
+			}
+			if !ok {
+				http.Error(w, "invalid signature", http.StatusForbidden)
+				return
+			}
+			l.serve(w, r, key)
+		case http.MethodPut:
+			meta := Meta{ContentType: r.Header.Get("Content-Type"), Size: r.ContentLength}
+			if meta.Size < 0 || !l.verify(r, http.MethodPut, key, meta) {
+				http.Error(w, "invalid signature", http.StatusForbidden)
+				return
+			}
+			if _, err := l.Put(r.Context(), key, http.MaxBytesReader(w, r.Body, meta.Size), meta); err != nil {
+				http.Error(w, "upload failed", http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func (l *Local) serve(w http.ResponseWriter, r *http.Request, key string) {
+	f, obj, err := l.Open(r.Context(), key)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", obj.ContentType)
+	w.Header().Set("ETag", `"`+obj.ETag+`"`)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", obj.ModTime, f.(io.ReadSeeker))
+}
+
+func (l *Local) sign(method, key string, exp int64, meta Meta) string {
+	mac := hmac.New(sha256.New, l.key)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%s\n%d", method, key, exp, meta.ContentType, meta.Size)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *Local) verify(r *http.Request, method, key string, meta Meta) bool {
+	q := r.URL.Query()
+	exp, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	want := l.sign(method, key, exp, meta)
+	return hmac.Equal([]byte(want), []byte(q.Get("signature")))
+}
+
+func (l *Local) isPublic(key string) bool {
+	for _, p := range l.public {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(key))
+}
+
+// detectFileType — тип по расширению, иначе по содержимому.
+func detectFileType(p string) (string, error) {
+	if ct := mime.TypeByExtension(filepath.Ext(p)); ct != "" {
+		return ct, nil
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return "", mapFSErr(err)
+	}
+	defer f.Close()
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+func mapFSErr(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
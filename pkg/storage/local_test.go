@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestLocal(t *testing.T) *Local {
+	t.Helper()
+	l, err := NewLocal(LocalOptions{
+		Dir:            t.TempDir(),
+		PublicBaseURL:  "http://media.test",
+		SigningKey:     "test-signing-key",
+		PublicPrefixes: []string{"avatars/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"avatars/u/64.png", "uploads/avatars/u/1"} {
+		if _, err := l.Put(context.Background(), key, bytes.NewReader([]byte("data")), Meta{Size: 4}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func get(t *testing.T, h http.Handler, rawURL string) int {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+	return rec.Code
+}
+
+func TestLocalHandlerGet(t *testing.T) {
+	l := newTestLocal(t)
+	h := l.Handler()
+	ctx := context.Background()
+
+	signed, err := l.PresignGet(ctx, "uploads/avatars/u/1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(signed)
+	q := u.Query()
+	q.Set("signature", q.Get("signature")[1:]+"0")
+	tampered := *u
+	tampered.RawQuery = q.Encode()
+
+	past := time.Now().Add(-time.Minute).Unix()
+	expired := *u
+	eq := url.Values{}
+	eq.Set("expires", strconv.FormatInt(past, 10))
+	eq.Set("signature", l.sign(http.MethodGet, "uploads/avatars/u/1", past, Meta{}))
+	expired.RawQuery = eq.Encode()
+
+	for _, tc := range []struct {
+		name string
+		url  string
+		want int
+	}{
+		{"public without signature", l.PublicURL("avatars/u/64.png"), http.StatusOK},
+		{"private without signature", l.PublicURL("uploads/avatars/u/1"), http.StatusForbidden},
+		{"private signed", signed, http.StatusOK},
+		{"tampered signature", tampered.String(), http.StatusForbidden},
+		{"expired signature", expired.String(), http.StatusForbidden},
+	} {
+		if got := get(t, h, tc.url); got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLocalHandlerPut(t *testing.T) {
+	l := newTestLocal(t)
+	h := l.Handler()
+	meta := Meta{ContentType: "image/png", Size: 3}
+
+	p, err := l.PresignPut(context.Background(), "uploads/avatars/u/2", meta, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	put := func(body string, ct string) int {
+		u, _ := url.Parse(p.URL)
+		req := httptest.NewRequest(http.MethodPut, u.RequestURI(), bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", ct)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if got := put("abcd", meta.ContentType); got != http.StatusForbidden {
+		t.Errorf("other size: got %d, want 403", got)
+	}
+	if got := put("abc", "text/html"); got != http.StatusForbidden {
+		t.Errorf("other content type: got %d, want 403", got)
+	}
+	if got := put("abc", meta.ContentType); got != http.StatusOK {
+		t.Errorf("signed upload: got %d, want 200", got)
+	}
+}
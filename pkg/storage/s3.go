@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type S3Options struct {
+	Bucket string
+	Region string
+	// Endpoint — host[:port] или URL; http:// отключает TLS (MinIO в docker-compose).
+	// Пусто — AWS S3.
+	Endpoint     string
+	AccessKey    string
+	SecretKey    string
+	UsePathStyle bool
+	// PublicBaseURL — CDN или адрес публичного бакета; пусто — ссылка через endpoint
+	PublicBaseURL string
+}
+
+// S3 — драйвер для S3-совместимых хранилищ (AWS, MinIO, R2 и т.п.).
+type S3 struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+func NewS3(ctx context.Context, o S3Options) (*S3, error) {
+	if o.Bucket == "" {
+		return nil, errors.New("storage: s3 bucket is required")
+	}
+
+	host, secure, err := parseEndpoint(o.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	lookup := minio.BucketLookupDNS
+	if o.UsePathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(host, &minio.Options{
+		Creds:        credentials.NewStaticV4(o.AccessKey, o.SecretKey, ""),
+		Secure:       secure,
+		Region:       o.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+
+	ok, err := client.BucketExists(ctx, o.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("storage: check bucket %s: %w", o.Bucket, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("storage: bucket %s does not exist", o.Bucket)
+	}
+
+	base := o.PublicBaseURL
+	if base == "" {
+		base = client.EndpointURL().String() + "/" + o.Bucket
+	}
+	return &S3{client: client, bucket: o.Bucket, baseURL: base}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, meta Meta) (Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+	size := meta.Size
+	if size < 0 {
+		size = -1
+	}
+	info, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: meta.ContentType})
+	if err != nil {
+		return Object{}, mapS3Err(err)
+	}
+	return Object{
+		Key:         key,
+		ContentType: meta.ContentType,
+		Size:        info.Size,
+		ETag:        info.ETag,
+		ModTime:     info.LastModified,
+	}, nil
+}
+
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	o, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, Object{}, mapS3Err(err)
+	}
+	// GetObject ленивый: ошибка отсутствия приходит только на первом обращении
+	info, err := o.Stat()
+	if err != nil {
+		_ = o.Close()
+		return nil, Object{}, mapS3Err(err)
+	}
+	return o, objectFromInfo(info), nil
+}
+
+func (s *S3) Stat(ctx context.Context, key string) (Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return Object{}, err
+	}
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return Object{}, mapS3Err(err)
+	}
+	return objectFromInfo(info), nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		if errors.Is(mapS3Err(err), ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *S3) PresignPut(ctx context.Context, key string, meta Meta, ttl time.Duration) (Presigned, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return Presigned{}, err
+	}
+	headers := http.Header{}
+	headers.Set("Content-Type", meta.ContentType)
+	headers.Set("Content-Length", strconv.FormatInt(meta.Size, 10))
+
+	u, err := s.client.PresignHeader(ctx, http.MethodPut, s.bucket, key, ttl, nil, headers)
+	if err != nil {
+		return Presigned{}, err
+	}
+	return Presigned{
+		Method: http.MethodPut,
+		URL:    u.String(),
+		Headers: map[string]string{
+			"Content-Type":   meta.ContentType,
+			"Content-Length": strconv.FormatInt(meta.Size, 10),
+		},
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+func (s *S3) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *S3) PublicURL(key string) string {
+	return joinURL(s.baseURL, key)
+}
+
+// Ping — для health-проверки.
+func (s *S3) Ping(ctx context.Context) error {
+	_, err := s.client.BucketExists(ctx, s.bucket)
+	return err
+}
+
+func objectFromInfo(info minio.ObjectInfo) Object {
+	return Object{
+		Key:         info.Key,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+		ETag:        info.ETag,
+		ModTime:     info.LastModified,
+	}
+}
+
+func parseEndpoint(endpoint string) (host string, secure bool, err error) {
+	if endpoint == "" {
+		return "s3.amazonaws.com", true, nil
+	}
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, true, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, fmt.Errorf("storage: bad S3 endpoint %q: %w", endpoint, err)
+	}
+	return u.Host, u.Scheme == "https", nil
+}
+
+func mapS3Err(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case minio.NoSuchKey, "NotFound":
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 — S3-совместимый сервер в памяти, как MinIO с одним бакетом (path-style).
+// Подписи не проверяются: тестируется драйвер, а не SigV4.
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+	etag        string
+	modTime     time.Time
+}
+
+func newFakeS3(t *testing.T, bucket string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(&fakeS3{bucket: bucket, objects: map[string]fakeObject{}})
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		s3Error(w, r, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	if key == "" {
+		if r.Method != http.MethodHead && r.Method != http.MethodGet {
+			s3Error(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := readPayload(r)
+		if err != nil {
+			s3Error(w, r, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		sum := md5.Sum(body)
+		obj := fakeObject{
+			data:        body,
+			contentType: r.Header.Get("Content-Type"),
+			etag:        hex.EncodeToString(sum[:]),
+			modTime:     time.Now().UTC().Truncate(time.Second),
+		}
+		f.objects[key] = obj
+		w.Header().Set("ETag", `"`+obj.etag+`"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		obj, ok := f.objects[key]
+		if !ok {
+			s3Error(w, r, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		h := w.Header()
+		h.Set("Content-Type", obj.contentType)
+		h.Set("Content-Length", strconv.Itoa(len(obj.data)))
+		h.Set("ETag", `"`+obj.etag+`"`)
+		h.Set("Last-Modified", obj.modTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(obj.data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s3Error(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// readPayload снимает aws-chunked обёртку, если клиент прислал потоковую подпись.
+func readPayload(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return body, nil
+	}
+	var out []byte
+	for {
+		line, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			return nil, errors.New("bad chunk header")
+		}
+		sizeHex, _, _ := bytes.Cut(line, []byte(";"))
+		n, err := strconv.ParseInt(string(sizeHex), 16, 64)
+		if err != nil || int64(len(rest)) < n {
+			return nil, errors.New("bad chunk size")
+		}
+		if n == 0 {
+			return out, nil
+		}
+		out = append(out, rest[:n]...)
+		body = bytes.TrimPrefix(rest[n:], []byte("\r\n"))
+	}
+}
+
+func s3Error(w http.ResponseWriter, r *http.Request, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message><Resource>%s</Resource></Error>`, code, code, r.URL.Path)
+	}
+}
+
+func newTestS3(t *testing.T, endpoint, bucket string) (*S3, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return NewS3(ctx, S3Options{
+		Bucket:       bucket,
+		Region:       "us-east-1",
+		Endpoint:     endpoint,
+		AccessKey:    "minio",
+		SecretKey:    "minio123",
+		UsePathStyle: true,
+	})
+}
+
+func TestS3Objects(t *testing.T) {
+	srv := newFakeS3(t, "life-rpg")
+	s, err := newTestS3(t, srv.URL, "life-rpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	const key = "avatars/01HX/64.png"
+	payload := []byte("not really a png")
+
+	obj, err := s.Put(ctx, key, bytes.NewReader(payload), Meta{ContentType: "image/png", Size: int64(len(payload))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Size != int64(len(payload)) || obj.ETag == "" {
+		t.Fatalf("put: unexpected object %+v", obj)
+	}
+
+	st, err := s.Stat(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.ContentType != "image/png" || st.Size != int64(len(payload)) {
+		t.Fatalf("stat: unexpected object %+v", st)
+	}
+
+	rc, _, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil || !bytes.Equal(got, payload) {
+		t.Fatalf("open: got %q, %v", got, err)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("stat after delete: want ErrNotFound, got %v", err)
+	}
+	if _, _, err := s.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("open after delete: want ErrNotFound, got %v", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("delete of missing object: %v", err)
+	}
+	if _, err := s.Put(ctx, "../escape", bytes.NewReader(nil), Meta{Size: 0}); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("put with bad key: want ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestS3MissingBucket(t *testing.T) {
+	srv := newFakeS3(t, "life-rpg")
+	if _, err := newTestS3(t, srv.URL, "other"); err == nil {
+		t.Fatal("want error for missing bucket")
+	}
+}
+
+func TestS3PresignPutSignsHeaders(t *testing.T) {
+	srv := newFakeS3(t, "life-rpg")
+	s, err := newTestS3(t, srv.URL, "life-rpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.PresignPut(context.Background(), "uploads/avatars/u/1", Meta{ContentType: "image/jpeg", Size: 42}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Method != http.MethodPut || p.Headers["Content-Length"] != "42" {
+		t.Fatalf("unexpected presigned request %+v", p)
+	}
+	if !strings.Contains(p.URL, "X-Amz-SignedHeaders=content-length%3Bcontent-type") {
+		t.Fatalf("content headers are not signed: %s", p.URL)
+	}
+}
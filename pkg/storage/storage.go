@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound    = errors.New("storage: object not found")
+	ErrInvalidKey  = errors.New("storage: invalid key")
+	ErrTooLarge    = errors.New("storage: object too large")
+	ErrContentType = errors.New("storage: content type not allowed")
+)
+
+// Storage — объектное хранилище. Ключи — относительные пути через "/"
+// (avatars/01HX.../v1.png), без ".." и ведущего слэша.
+type Storage interface {
+	// Put пишет объект; meta.Size < 0 — размер заранее неизвестен.
+	Put(ctx context.Context, key string, r io.Reader, meta Meta) (Object, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	Stat(ctx context.Context, key string) (Object, error)
+	// Delete не считает ошибкой отсутствие объекта.
+	Delete(ctx context.Context, key string) error
+
+	// PresignPut — ссылка для загрузки напрямую клиентом. Content-Type и
+	// Content-Length подписываются: запрос с другими значениями отклоняется.
+	PresignPut(ctx context.Context, key string, meta Meta, ttl time.Duration) (Presigned, error)
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// PublicURL — постоянная ссылка на объект в публичном хранилище (CDN, public-read бакет).
+	PublicURL(key string) string
+}
+
+type Meta struct {
+	ContentType string
+	Size        int64
+}
+
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+	ETag        string
+	ModTime     time.Time
+}
+
+// Presigned — запрос, который клиент должен выполнить как есть, вместе с Headers.
+type Presigned struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+type Config struct {
+	Driver        string
+	PublicBaseURL string
+	PresignTTL    time.Duration
+
+	LocalDir string
+	// SigningKey подписывает presigned-ссылки локального драйвера
+	SigningKey string
+	// LocalPublicPrefixes — ключи, которые локальный драйвер отдаёт без подписи
+	LocalPublicPrefixes []string
+
+	S3Bucket    string
+	S3Region    string
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+	S3UsePath   bool
+}
+
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NewLocal(LocalOptions{
+			Dir:            cfg.LocalDir,
+			PublicBaseURL:  cfg.PublicBaseURL,
+			SigningKey:     cfg.SigningKey,
+			PublicPrefixes: cfg.LocalPublicPrefixes,
+		})
+	case DriverS3:
+		return NewS3(ctx, S3Options{
+			Bucket:        cfg.S3Bucket,
+			Region:        cfg.S3Region,
+			Endpoint:      cfg.S3Endpoint,
+			AccessKey:     cfg.S3AccessKey,
+			SecretKey:     cfg.S3SecretKey,
+			UsePathStyle:  cfg.S3UsePath,
+			PublicBaseURL: cfg.PublicBaseURL,
+		})
+	default:
+		return nil, fmt.Errorf("storage: unsupported driver %q", cfg.Driver)
+	}
+}
+
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	c := path.Clean(key)
+	if c != key || c == "." || c == ".." || strings.HasPrefix(c, "../") {
+		return "", ErrInvalidKey
+	}
+	return c, nil
+}
+
+// joinURL склеивает базовый URL и ключ, экранируя сегменты пути.
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + (&url.URL{Path: key}).EscapedPath()
+}
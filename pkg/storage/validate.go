@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+)
+
+// sniffLen — сколько байт смотрит http.DetectContentType.
+const sniffLen = 512
+
+// Limits — ограничения на загружаемые объекты.
+type Limits struct {
+	MaxSize int64
+	// AllowedTypes — MIME-типы без параметров (image/png); пусто — любые
+	AllowedTypes []string
+}
+
+// Check проверяет заявленные клиентом тип и размер, например перед PresignPut
+// или по Stat уже загруженного объекта.
+func (l Limits) Check(m Meta) error {
+	if m.Size < 0 || (l.MaxSize > 0 && m.Size > l.MaxSize) {
+		return fmt.Errorf("%w: %d bytes, max %d", ErrTooLarge, m.Size, l.MaxSize)
+	}
+	return l.checkType(m.ContentType)
+}
+
+// Reader проверяет содержимое потока: тип определяется по первым байтам и
+// должен совпадать с заявленным, а чтение сверх MaxSize вернёт ErrTooLarge.
+// Возвращает поток для записи в хранилище и итоговый Content-Type.
+func (l Limits) Reader(r io.Reader, declared string) (io.Reader, string, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, "", err
+	}
+
+	detected := baseType(http.DetectContentType(head))
+	if declared != "" && baseType(declared) != detected {
+		return nil, "", fmt.Errorf("%w: declared %s, detected %s", ErrContentType, baseType(declared), detected)
+	}
+	if err := l.checkType(detected); err != nil {
+		return nil, "", err
+	}
+
+	if l.MaxSize <= 0 {
+		return br, detected, nil
+	}
+	return &limitReader{r: br, left: l.MaxSize}, detected, nil
+}
+
+func (l Limits) checkType(ct string) error {
+	if len(l.AllowedTypes) == 0 {
+		return nil
+	}
+	if !slices.Contains(l.AllowedTypes, baseType(ct)) {
+		return fmt.Errorf("%w: %q", ErrContentType, ct)
+	}
+	return nil
+}
+
+func baseType(ct string) string {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ct
+	}
+	return mt
+}
+
+// limitReader в отличие от io.LimitReader сообщает о превышении ошибкой,
+// а не обрезает объект молча.
+type limitReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (lr *limitReader) Read(p []byte) (int, error) {
+	if lr.left < 0 {
+		return 0, ErrTooLarge
+	}
+	// читаем на байт больше лимита, чтобы отличить "ровно MaxSize" от превышения
+	if int64(len(p)) > lr.left+1 {
+		p = p[:lr.left+1]
+	}
+	n, err := lr.r.Read(p)
+	lr.left -= int64(n)
+	if lr.left < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,89 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ServerConfig — mTLS на стороне сервера: клиентский сертификат обязателен,
+// проверяется по текущему CA, а его SAN должен быть в allowedPeers
+// (пусто — пускаем любого с валидным сертификатом).
+func (r *Reloader) ServerConfig(allowedPeers []string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// конфиг на каждый handshake — чтобы подхватывать перечитанный CA
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.Certificate()},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    r.Pool(),
+				NextProtos:   []string{"h2"},
+				VerifyConnection: func(cs tls.ConnectionState) error {
+					return checkPeer(cs, allowedPeers)
+				},
+			}, nil
+		},
+	}
+}
+
+// ClientConfig — клиентская сторона: предъявляем свой сертификат и проверяем
+// сервер по текущему CA и имени из target (ServerName ставит gRPC).
+// Стандартная проверка выключена только потому, что RootCAs нельзя подменить
+// на лету — та же проверка делается в VerifyConnection.
+func (r *Reloader) ClientConfig(allowedPeers []string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if err := r.verifyServer(cs); err != nil {
+				return err
+			}
+			return checkPeer(cs, allowedPeers)
+		},
+	}
+}
+
+func (r *Reloader) verifyServer(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tlsx: server presented no certificate")
+	}
+	inter := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		inter.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         r.Pool(),
+		Intermediates: inter,
+	})
+	return err
+}
+
+// checkPeer сверяет DNS/URI SAN сертификата пира со списком разрешённых.
+func checkPeer(cs tls.ConnectionState, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tlsx: peer presented no certificate")
+	}
+	leaf := cs.PeerCertificates[0]
+	for _, n := range leaf.DNSNames {
+		if slices.Contains(allowed, n) {
+			return nil
+		}
+	}
+	for _, u := range leaf.URIs {
+		if slices.Contains(allowed, u.String()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("tlsx: peer %v not in allowlist", leaf.DNSNames)
+}
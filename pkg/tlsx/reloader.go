@@ -0,0 +1,131 @@
+package tlsx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hassiimykyta/life-rpg/pkg/logx"
+)
+
+type Options struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// ReloadInterval — как часто сверять mtime файлов; 0 — без перечитывания.
+	ReloadInterval time.Duration
+}
+
+// Reloader держит текущую пару cert/key и пул CA и перечитывает их с диска,
+// когда файлы меняются (cert-manager/vault ротируют их на месте).
+type Reloader struct {
+	opts Options
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+func NewReloader(opts Options) (*Reloader, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" || opts.CAFile == "" {
+		return nil, errors.New("tlsx: cert, key and CA files are required")
+	}
+	r := &Reloader{opts: opts}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Run перечитывает файлы до отмены ctx. Ошибка чтения не роняет сервис:
+// продолжаем работать со старым сертификатом.
+func (r *Reloader) Run(ctx context.Context) {
+	if r.opts.ReloadInterval <= 0 {
+		return
+	}
+	t := time.NewTicker(r.opts.ReloadInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			mt, err := r.latestModTime()
+			if err != nil {
+				slog.Warn("tls stat failed", logx.Err(err))
+				continue
+			}
+			r.mu.RLock()
+			changed := mt.After(r.modTime)
+			r.mu.RUnlock()
+			if !changed {
+				continue
+			}
+			if err := r.load(); err != nil {
+				slog.Error("tls reload failed, keeping previous certificate", logx.Err(err))
+				continue
+			}
+			slog.Info("tls certificates reloaded", "cert", r.opts.CertFile)
+		}
+	}
+}
+
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+func (r *Reloader) Pool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+func (r *Reloader) load() error {
+	mt, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tlsx: load key pair: %w", err)
+	}
+	caPEM, err := os.ReadFile(r.opts.CAFile)
+	if err != nil {
+		return fmt.Errorf("tlsx: read CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("tlsx: no certificates in %s", r.opts.CAFile)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.pool = pool
+	r.modTime = mt
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Reloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, f := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.CAFile} {
+		st, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if st.ModTime().After(latest) {
+			latest = st.ModTime()
+		}
+	}
+	return latest, nil
+}
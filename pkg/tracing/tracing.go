@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type Options struct {
+	Service     string
+	Env         string
+	Endpoint    string // host:port OTLP/gRPC коллектора; пусто — спаны не экспортируются
+	Insecure    bool
+	SampleRatio float64
+}
+
+// Init настраивает глобальные TracerProvider и propagator (W3C tracecontext + baggage).
+// Propagator ставится всегда, чтобы контекст пробрасывался дальше даже без экспорта.
+// Возвращённый shutdown дописывает буфер спанов в коллектор.
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+	}
+	exp, err := otlptracegrpc.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", opts.Service),
+		attribute.String("deployment.environment", opts.Env),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	// 0 — свои трейсы не начинаем, но решение вызывающего сервиса соблюдаем
+	root := sdktrace.NeverSample()
+	switch ratio := opts.SampleRatio; {
+	case ratio >= 1:
+		root = sdktrace.AlwaysSample()
+	case ratio > 0:
+		root = sdktrace.TraceIDRatioBased(ratio)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(root)),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,33 @@
+package ulid
+
+import (
+	commonv1 "github.com/hassiimykyta/life-rpg/services/common/v1"
+)
+
+// UserID — ULID пользователя; конструируется через ParseUserID/UserIDFromProto
+// или генератор, чтобы на границе сервиса не пролезли произвольные строки.
+type UserID string
+
+func ParseUserID(s string) (UserID, error) {
+	id, err := Parse(s)
+	return UserID(id), err
+}
+
+func UserIDFromProto(p *commonv1.UserId) (UserID, error) {
+	return ParseUserID(p.GetValue())
+}
+
+func (id UserID) String() string { return string(id) }
+
+func (id UserID) Proto() *commonv1.UserId {
+	return &commonv1.UserId{Value: string(id)}
+}
+
+type QuestID string
+
+func ParseQuestID(s string) (QuestID, error) {
+	id, err := Parse(s)
+	return QuestID(id), err
+}
+
+func (id QuestID) String() string { return string(id) }
@@ -1,18 +1,94 @@
 package ulid
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/oklog/ulid"
 )
 
-type ULIDGenerator struct{}
+var ErrInvalid = errors.New("ulid: invalid id")
 
-func NewULIDGenerator() *ULIDGenerator { return &ULIDGenerator{} }
+type Option func(*ULIDGenerator)
+
+// WithClock подменяет источник времени — для тестов.
+func WithClock(now func() time.Time) Option {
+	return func(g *ULIDGenerator) { g.now = now }
+}
+
+// ULIDGenerator выдаёт монотонно возрастающие ID: в пределах одной миллисекунды
+// энтропия инкрементируется, а не берётся заново. Безопасен для горутин.
+type ULIDGenerator struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entropy io.Reader
+	lastMs  uint64
+}
+
+func NewULIDGenerator(opts ...Option) *ULIDGenerator {
+	g := &ULIDGenerator{
+		now:     time.Now,
+		entropy: ulid.Monotonic(rand.Reader, 0),
+	}
+	for _, o := range opts {
+		o(g)
+	}
+	return g
+}
 
 func (g *ULIDGenerator) New() (string, error) {
-	t := time.Now()
-	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
-	return ulid.MustNew(ulid.Timestamp(t), entropy).String(), nil
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := ulid.Timestamp(g.now())
+	// часы могли отступить назад (NTP) — остаёмся в прошлой миллисекунде,
+	// иначе новый ID окажется меньше уже выданного
+	if ms < g.lastMs {
+		ms = g.lastMs
+	}
+	id, err := ulid.New(ms, g.entropy)
+	if err != nil {
+		return "", fmt.Errorf("ulid: %w", err)
+	}
+	g.lastMs = ms
+	return id.String(), nil
+}
+
+func (g *ULIDGenerator) NewUserID() (UserID, error) {
+	id, err := g.New()
+	return UserID(id), err
+}
+
+func (g *ULIDGenerator) NewQuestID() (QuestID, error) {
+	id, err := g.New()
+	return QuestID(id), err
+}
+
+// Parse проверяет строку и возвращает ID в каноническом виде (верхний регистр).
+func Parse(s string) (string, error) {
+	id, err := ulid.ParseStrict(strings.TrimSpace(s))
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalid, s, err)
+	}
+	return id.String(), nil
+}
+
+func Valid(s string) bool {
+	_, err := ulid.ParseStrict(s)
+	return err == nil
+}
+
+// Time — момент создания, зашитый в ID.
+func Time(s string) (time.Time, error) {
+	id, err := ulid.ParseStrict(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q: %v", ErrInvalid, s, err)
+	}
+	return ulid.Time(id.Time()), nil
 }
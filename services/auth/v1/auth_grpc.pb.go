@@ -28,10 +28,17 @@ const (
 // AuthServiceClient is the client API for AuthService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HTTP-маршруты gateway строит по google.api.http; методы без аннотации
+// наружу не публикуются. Коды gRPC переводятся в HTTP (resp.GRPCStatus):
+// INVALID_ARGUMENT — 400, UNAUTHENTICATED (неверный логин или пароль) — 401,
+// ALREADY_EXISTS (email или username заняты) — 409. До перехода на
+// транскодирование любая ошибка входа и регистрации была 400.
 type AuthServiceClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	CheckAvailability(ctx context.Context, in *CheckAvailabilityRequest, opts ...grpc.CallOption) (*CheckAvailabilityResponse, error)
+	// только для сервисов: по username отдаёт email
 	Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error)
 }
 
@@ -86,10 +93,17 @@ func (c *authServiceClient) Resolve(ctx context.Context, in *ResolveRequest, opt
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
+//
+// HTTP-маршруты gateway строит по google.api.http; методы без аннотации
+// наружу не публикуются. Коды gRPC переводятся в HTTP (resp.GRPCStatus):
+// INVALID_ARGUMENT — 400, UNAUTHENTICATED (неверный логин или пароль) — 401,
+// ALREADY_EXISTS (email или username заняты) — 409. До перехода на
+// транскодирование любая ошибка входа и регистрации была 400.
 type AuthServiceServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
 	CheckAvailability(context.Context, *CheckAvailabilityRequest) (*CheckAvailabilityResponse, error)
+	// только для сервисов: по username отдаёт email
 	Resolve(context.Context, *ResolveRequest) (*ResolveResponse, error)
 	mustEmbedUnimplementedAuthServiceServer()
 }
@@ -0,0 +1,83 @@
+package authv1
+
+import (
+	"errors"
+	"strings"
+)
+
+// Validate-методы проверяют только форму запроса; их вызывает
+// grpcserver.ValidateUnaryInterceptor до хендлера.
+
+// MinPasswordLen — минимальная длина пароля при регистрации.
+const MinPasswordLen = 6
+
+func blank(s string) bool { return strings.TrimSpace(s) == "" }
+
+func (x *RegisterRequest) Validate() error {
+	switch {
+	case blank(x.GetEmail()):
+		return errors.New("email required")
+	case blank(x.GetUsername()):
+		return errors.New("username required")
+	case len(x.GetPassword()) < MinPasswordLen:
+		return errors.New("password too short")
+	}
+	return nil
+}
+
+func (x *LoginRequest) Validate() error {
+	if x.GetPassword() == "" {
+		return errors.New("password required")
+	}
+	switch sub := x.GetSubject().(type) {
+	case *LoginRequest_Email:
+		if blank(sub.Email) {
+			return errors.New("email required")
+		}
+	case *LoginRequest_Username:
+		if blank(sub.Username) {
+			return errors.New("username required")
+		}
+	case *LoginRequest_UserId:
+		if sub.UserId == "" {
+			return errors.New("user id required")
+		}
+	default:
+		return errors.New("oneof subject required")
+	}
+	return nil
+}
+
+func (x *CheckAvailabilityRequest) Validate() error {
+	if blank(x.GetEmail()) && blank(x.GetUsername()) {
+		return errors.New("email or username required")
+	}
+	return nil
+}
+
+func (x *VerifyTokenRequest) Validate() error {
+	if x.GetAccessToken() == "" {
+		return errors.New("access token required")
+	}
+	return nil
+}
+
+func (x *ResolveRequest) Validate() error {
+	switch key := x.GetKey().(type) {
+	case *ResolveRequest_Email:
+		if blank(key.Email) {
+			return errors.New("email required")
+		}
+	case *ResolveRequest_Username:
+		if blank(key.Username) {
+			return errors.New("username required")
+		}
+	case *ResolveRequest_UserId:
+		if key.UserId == "" {
+			return errors.New("user id required")
+		}
+	default:
+		return errors.New("oneof key required")
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: events/notification/v1/notification_events.proto
+
+package notificationeventsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// InboxNotificationRequested публикуется в топик "notification.inbox"
+// любым сервисом, который хочет уведомить пользователя о событии.
+// Каналы (in-app/email/дайджест) выбирает notification-svc по настройкам пользователя.
+type InboxNotificationRequested struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         string                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"` // "notification.inbox"
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	Data          map[string]string      `protobuf:"bytes,6,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	OccurredAt    int64                  `protobuf:"varint,7,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"` // unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboxNotificationRequested) Reset() {
+	*x = InboxNotificationRequested{}
+	mi := &file_events_notification_v1_notification_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboxNotificationRequested) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboxNotificationRequested) ProtoMessage() {}
+
+func (x *InboxNotificationRequested) ProtoReflect() protoreflect.Message {
+	mi := &file_events_notification_v1_notification_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboxNotificationRequested.ProtoReflect.Descriptor instead.
+func (*InboxNotificationRequested) Descriptor() ([]byte, []int) {
+	return file_events_notification_v1_notification_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InboxNotificationRequested) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *InboxNotificationRequested) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *InboxNotificationRequested) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *InboxNotificationRequested) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *InboxNotificationRequested) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *InboxNotificationRequested) GetData() map[string]string {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *InboxNotificationRequested) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+// InboxItemCreated публикуется notification-svc в топик "notification.inbox.created"
+// после записи элемента инбокса. Каждая реплика читает топик своей группой
+// и отдаёт элемент в открытые на ней стримы StreamInbox.
+type InboxItemCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	Data          map[string]string      `protobuf:"bytes,6,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // unix millis
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboxItemCreated) Reset() {
+	*x = InboxItemCreated{}
+	mi := &file_events_notification_v1_notification_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboxItemCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboxItemCreated) ProtoMessage() {}
+
+func (x *InboxItemCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_notification_v1_notification_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboxItemCreated.ProtoReflect.Descriptor instead.
+func (*InboxItemCreated) Descriptor() ([]byte, []int) {
+	return file_events_notification_v1_notification_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *InboxItemCreated) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *InboxItemCreated) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *InboxItemCreated) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *InboxItemCreated) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *InboxItemCreated) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *InboxItemCreated) GetData() map[string]string {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *InboxItemCreated) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+var File_events_notification_v1_notification_events_proto protoreflect.FileDescriptor
+
+const file_events_notification_v1_notification_events_proto_rawDesc = "" +
+	"\n" +
+	"0events/notification/v1/notification_events.proto\x12\x16events.notification.v1\"\xb5\x02\n" +
+	"\x1aInboxNotificationRequested\x12\x14\n" +
+	"\x05event\x18\x01 \x01(\tR\x05event\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\x12P\n" +
+	"\x04data\x18\x06 \x03(\v2<.events.notification.v1.InboxNotificationRequested.DataEntryR\x04data\x12\x1f\n" +
+	"\voccurred_at\x18\a \x01(\x03R\n" +
+	"occurredAt\x1a7\n" +
+	"\tDataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x99\x02\n" +
+	"\x10InboxItemCreated\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\x12F\n" +
+	"\x04data\x18\x06 \x03(\v22.events.notification.v1.InboxItemCreated.DataEntryR\x04data\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x1a7\n" +
+	"\tDataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01BWZUgithub.com/hassiimykyta/life-rpg/services/events/notification/v1;notificationeventsv1b\x06proto3"
+
+var (
+	file_events_notification_v1_notification_events_proto_rawDescOnce sync.Once
+	file_events_notification_v1_notification_events_proto_rawDescData []byte
+)
+
+func file_events_notification_v1_notification_events_proto_rawDescGZIP() []byte {
+	file_events_notification_v1_notification_events_proto_rawDescOnce.Do(func() {
+		file_events_notification_v1_notification_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_notification_v1_notification_events_proto_rawDesc), len(file_events_notification_v1_notification_events_proto_rawDesc)))
+	})
+	return file_events_notification_v1_notification_events_proto_rawDescData
+}
+
+var file_events_notification_v1_notification_events_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_events_notification_v1_notification_events_proto_goTypes = []any{
+	(*InboxNotificationRequested)(nil), // 0: events.notification.v1.InboxNotificationRequested
+	(*InboxItemCreated)(nil),           // 1: events.notification.v1.InboxItemCreated
+	nil,                                // 2: events.notification.v1.InboxNotificationRequested.DataEntry
+	nil,                                // 3: events.notification.v1.InboxItemCreated.DataEntry
+}
+var file_events_notification_v1_notification_events_proto_depIdxs = []int32{
+	2, // 0: events.notification.v1.InboxNotificationRequested.data:type_name -> events.notification.v1.InboxNotificationRequested.DataEntry
+	3, // 1: events.notification.v1.InboxItemCreated.data:type_name -> events.notification.v1.InboxItemCreated.DataEntry
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_events_notification_v1_notification_events_proto_init() }
+func file_events_notification_v1_notification_events_proto_init() {
+	if File_events_notification_v1_notification_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_notification_v1_notification_events_proto_rawDesc), len(file_events_notification_v1_notification_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_notification_v1_notification_events_proto_goTypes,
+		DependencyIndexes: file_events_notification_v1_notification_events_proto_depIdxs,
+		MessageInfos:      file_events_notification_v1_notification_events_proto_msgTypes,
+	}.Build()
+	File_events_notification_v1_notification_events_proto = out.File
+	file_events_notification_v1_notification_events_proto_goTypes = nil
+	file_events_notification_v1_notification_events_proto_depIdxs = nil
+}
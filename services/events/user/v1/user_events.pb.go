@@ -28,6 +28,7 @@ type UserRegistered struct {
 	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
 	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
 	OccurredAt    int64                  `protobuf:"varint,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"` // unix seconds (полезно для отладки/идемпотентности)
+	Locale        string                 `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,18 +98,26 @@ func (x *UserRegistered) GetOccurredAt() int64 {
 	return 0
 }
 
+func (x *UserRegistered) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 var File_events_user_v1_user_events_proto protoreflect.FileDescriptor
 
 const file_events_user_v1_user_events_proto_rawDesc = "" +
 	"\n" +
-	" events/user/v1/user_events.proto\x12\x0eevents.user.v1\"\x92\x01\n" +
+	" events/user/v1/user_events.proto\x12\x0eevents.user.v1\"\xaa\x01\n" +
 	"\x0eUserRegistered\x12\x14\n" +
 	"\x05event\x18\x01 \x01(\tR\x05event\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1a\n" +
 	"\busername\x18\x04 \x01(\tR\busername\x12\x1f\n" +
 	"\voccurred_at\x18\x05 \x01(\x03R\n" +
-	"occurredAtBGZEgithub.com/hassiimykyta/life-rpg/services/events/user/v1;usereventsv1b\x06proto3"
+	"occurredAt\x12\x16\n" +
+	"\x06locale\x18\x06 \x01(\tR\x06localeBGZEgithub.com/hassiimykyta/life-rpg/services/events/user/v1;usereventsv1b\x06proto3"
 
 var (
 	file_events_user_v1_user_events_proto_rawDescOnce sync.Once
@@ -0,0 +1,35 @@
+package userv1
+
+import (
+	"errors"
+	"strings"
+)
+
+// Validate-методы проверяют только форму запроса; их вызывает
+// grpcserver.ValidateUnaryInterceptor до хендлера. Формат ULID проверяет сервис.
+
+func (x *GetUserRequest) Validate() error {
+	if x.GetId().GetValue() == "" {
+		return errors.New("user id required")
+	}
+	return nil
+}
+
+func (x *GetUserByEmailRequest) Validate() error {
+	if strings.TrimSpace(x.GetEmail()) == "" {
+		return errors.New("email required")
+	}
+	return nil
+}
+
+func (x *UpdateAvatarRequest) Validate() error {
+	if x.GetUserId().GetValue() == "" {
+		return errors.New("user id required")
+	}
+	for _, a := range x.GetAvatars() {
+		if a.GetSize() <= 0 || a.GetUrl() == "" {
+			return errors.New("avatar size and url required")
+		}
+	}
+	return nil
+}